@@ -0,0 +1,283 @@
+package csv
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charset is the character set of the CSV input or output.
+type Charset int
+
+const (
+	// UTF8 is the UTF-8 encoding, the default character set.
+	UTF8 Charset = iota
+	// UTF16LE is the UTF-16 little-endian encoding.
+	UTF16LE
+	// UTF16BE is the UTF-16 big-endian encoding.
+	UTF16BE
+	// ISO88591 is the ISO-8859-1 (Latin-1) encoding.
+	ISO88591
+	// Windows1252 is the Windows-1252 (CP-1252) encoding.
+	Windows1252
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// windows1252Runes maps the bytes 0x80 to 0x9F of Windows-1252 to runes. The
+// undefined bytes are mapped to the C1 control characters of the same code.
+var windows1252Runes = [32]rune{
+	0x20AC, 0x0081, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008D, 0x017D, 0x008F,
+	0x0090, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0x009D, 0x017E, 0x0178,
+}
+
+// charsetReader transcodes the underlying reader from the character set to
+// UTF-8, and strips the byte order mark at the beginning of the input. For
+// UTF-8 and UTF-16 inputs, a UTF-16 byte order mark overrides the charset.
+type charsetReader struct {
+	r       *bufio.Reader
+	charset Charset
+	checked bool
+	skipped int
+	buf     []byte
+}
+
+func newCharsetReader(r io.Reader, charset Charset) *charsetReader {
+	return &charsetReader{
+		r:       bufio.NewReader(r),
+		charset: charset,
+	}
+}
+
+func (cr *charsetReader) Read(p []byte) (int, error) {
+	if !cr.checked {
+		cr.checked = true
+		cr.detectBOM()
+	}
+
+	if cr.charset == UTF8 {
+		return cr.r.Read(p)
+	}
+
+	for len(cr.buf) == 0 {
+		if err := cr.fill(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, cr.buf)
+	cr.buf = cr.buf[n:]
+	return n, nil
+}
+
+// detectBOM strips the byte order mark at the beginning of the input, and
+// switches the charset by the byte order mark.
+func (cr *charsetReader) detectBOM() {
+	if cr.charset != UTF8 && cr.charset != UTF16LE && cr.charset != UTF16BE {
+		return
+	}
+
+	b, _ := cr.r.Peek(len(utf8BOM))
+	switch {
+	case cr.charset == UTF8 && len(b) == len(utf8BOM) && string(b) == string(utf8BOM):
+		cr.skipped, _ = cr.r.Discard(len(utf8BOM))
+	case len(b) >= 2 && string(b[:2]) == string(utf16LEBOM):
+		cr.charset = UTF16LE
+		_, _ = cr.r.Discard(len(utf16LEBOM))
+	case len(b) >= 2 && string(b[:2]) == string(utf16BEBOM):
+		cr.charset = UTF16BE
+		_, _ = cr.r.Discard(len(utf16BEBOM))
+	}
+}
+
+// fill decodes the next chunk of the input into the buffer.
+func (cr *charsetReader) fill() error {
+	switch cr.charset {
+	case UTF16LE, UTF16BE:
+		return cr.fillUTF16()
+	default:
+		return cr.fillSingleByte()
+	}
+}
+
+func (cr *charsetReader) fillSingleByte() error {
+	var chunk [512]byte
+	n, err := cr.r.Read(chunk[:])
+	if n == 0 {
+		return err
+	}
+
+	for _, b := range chunk[:n] {
+		r := rune(b)
+		if cr.charset == Windows1252 && b >= 0x80 && b < 0xA0 {
+			r = windows1252Runes[b-0x80]
+		}
+		cr.buf = utf8.AppendRune(cr.buf, r)
+	}
+
+	return nil
+}
+
+func (cr *charsetReader) fillUTF16() error {
+	for {
+		r, err := cr.readUTF16Rune()
+		if err != nil {
+			if len(cr.buf) > 0 && err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		cr.buf = utf8.AppendRune(cr.buf, r)
+
+		// stop at the end of the buffered input to avoid blocking
+		if cr.r.Buffered() < 2 || len(cr.buf) >= 512 {
+			return nil
+		}
+	}
+}
+
+func (cr *charsetReader) readUTF16Unit() (uint16, error) {
+	var b [2]byte
+	if _, err := io.ReadFull(cr.r, b[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return utf8.RuneError, nil
+		}
+		return 0, err
+	}
+
+	if cr.charset == UTF16BE {
+		return uint16(b[0])<<8 | uint16(b[1]), nil
+	}
+	return uint16(b[1])<<8 | uint16(b[0]), nil
+}
+
+func (cr *charsetReader) readUTF16Rune() (rune, error) {
+	u, err := cr.readUTF16Unit()
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(rune(u)) {
+		return rune(u), nil
+	}
+
+	// a high surrogate must be followed by a low surrogate
+	if b, err := cr.r.Peek(2); err == nil {
+		low := uint16(b[1])<<8 | uint16(b[0])
+		if cr.charset == UTF16BE {
+			low = uint16(b[0])<<8 | uint16(b[1])
+		}
+		if r := utf16.DecodeRune(rune(u), rune(low)); r != utf8.RuneError {
+			_, _ = cr.r.Discard(2)
+			return r, nil
+		}
+	}
+
+	return utf8.RuneError, nil
+}
+
+// charsetWriter transcodes the UTF-8 output to the character set of the
+// underlying writer.
+type charsetWriter struct {
+	w       io.Writer
+	charset Charset
+	pending []byte
+	buf     []byte
+}
+
+func newCharsetWriter(w io.Writer, charset Charset) *charsetWriter {
+	return &charsetWriter{
+		w:       w,
+		charset: charset,
+	}
+}
+
+func (cw *charsetWriter) Write(p []byte) (int, error) {
+	data := p
+	if len(cw.pending) > 0 {
+		data = append(cw.pending, p...)
+		cw.pending = nil
+	}
+
+	cw.buf = cw.buf[:0]
+	for len(data) > 0 {
+		if !utf8.FullRune(data) {
+			// keep the incomplete rune for the next write
+			cw.pending = append([]byte(nil), data...)
+			break
+		}
+
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		if err := cw.encodeRune(r); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := cw.w.Write(cw.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (cw *charsetWriter) encodeRune(r rune) error {
+	switch cw.charset {
+	case UTF16LE, UTF16BE:
+		for _, u := range utf16.AppendRune(nil, r) {
+			if cw.charset == UTF16BE {
+				cw.buf = append(cw.buf, byte(u>>8), byte(u))
+			} else {
+				cw.buf = append(cw.buf, byte(u), byte(u>>8))
+			}
+		}
+	case ISO88591:
+		if r > 0xFF {
+			return ErrInvalidCharacter
+		}
+		cw.buf = append(cw.buf, byte(r))
+	case Windows1252:
+		if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+			cw.buf = append(cw.buf, byte(r))
+			return nil
+		}
+		for i, wr := range windows1252Runes {
+			if wr == r {
+				cw.buf = append(cw.buf, byte(0x80+i))
+				return nil
+			}
+		}
+		return ErrInvalidCharacter
+	default:
+		cw.buf = utf8.AppendRune(cw.buf, r)
+	}
+
+	return nil
+}
+
+// bomWriter writes the UTF-8 byte order mark before the first write to the
+// underlying writer.
+type bomWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func newBOMWriter(w io.Writer) *bomWriter {
+	return &bomWriter{w: w}
+}
+
+func (bw *bomWriter) Write(p []byte) (int, error) {
+	if !bw.written {
+		bw.written = true
+		if _, err := bw.w.Write(utf8BOM); err != nil {
+			return 0, err
+		}
+	}
+
+	return bw.w.Write(p)
+}
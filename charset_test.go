@@ -0,0 +1,89 @@
+package csv_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ghosind/go-assert"
+	"github.com/ghosind/go-csv"
+)
+
+func TestDecoderWithUTF16Charset(t *testing.T) {
+	a := assert.New(t)
+	data := []byte{
+		'I', 0, 'D', 0, ',', 0, 'N', 0, 'a', 0, 'm', 0, 'e', 0, '\n', 0,
+		'1', 0, ',', 0, 'J', 0, 0xF6, 0, 'r', 0, 'g', 0, ' ', 0, 0x3D, 0xD8, 0x00, 0xDE, '\n', 0,
+	}
+	var samples []NoTagStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader(data), csv.WithCharset(csv.UTF16LE))
+	err := decoder.Decode(&samples)
+	a.NilNow(err)
+	a.EqualNow([]NoTagStruct{{ID: 1, Name: "Jörg 😀"}}, samples)
+}
+
+func TestDecoderWithUTF16CharsetDetection(t *testing.T) {
+	a := assert.New(t)
+	data := []byte{
+		0xFE, 0xFF, // BOM
+		0, 'I', 0, 'D', 0, ',', 0, 'N', 0, 'a', 0, 'm', 0, 'e', 0, '\n',
+		0, '1', 0, ',', 0, 'J', 0, 0xF6, 0, 'r', 0, 'g', 0, ' ', 0xD8, 0x3D, 0xDE, 0x00, 0, '\n',
+	}
+	var samples []NoTagStruct
+
+	err := csv.Unmarshal(data, &samples)
+	a.NilNow(err)
+	a.EqualNow([]NoTagStruct{{ID: 1, Name: "Jörg 😀"}}, samples)
+}
+
+func TestDecoderWithWindows1252Charset(t *testing.T) {
+	a := assert.New(t)
+	data := []byte("ID,Name\n1,Jos\xe9 \x80\x99\n")
+	var samples []NoTagStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader(data), csv.WithCharset(csv.Windows1252))
+	err := decoder.Decode(&samples)
+	a.NilNow(err)
+	a.EqualNow([]NoTagStruct{{ID: 1, Name: "José €™"}}, samples)
+
+	decoder = csv.NewDecoder(bytes.NewReader(data), csv.WithCharset(csv.ISO88591))
+	err = decoder.Decode(&samples)
+	a.NilNow(err)
+	a.EqualNow([]NoTagStruct{{ID: 1, Name: "José \u0080\u0099"}}, samples)
+}
+
+func TestEncoderWithUTF16Charset(t *testing.T) {
+	a := assert.New(t)
+	sample := NoTagStruct{ID: 1, Name: "Jörg 😀"}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithCharset(csv.UTF16LE), csv.WithBOM(true))
+	err := encoder.Encode(sample)
+	a.NilNow(err)
+	expected := []byte{
+		0xFF, 0xFE,
+		'I', 0, 'D', 0, ',', 0, 'N', 0, 'a', 0, 'm', 0, 'e', 0, '\n', 0,
+		'1', 0, ',', 0, 'J', 0, 0xF6, 0, 'r', 0, 'g', 0, ' ', 0, 0x3D, 0xD8, 0x00, 0xDE, '\n', 0,
+	}
+	a.EqualNow(expected, buf.Bytes())
+
+	var samples []NoTagStruct
+	err = csv.Unmarshal(buf.Bytes(), &samples)
+	a.NilNow(err)
+	a.EqualNow([]NoTagStruct{sample}, samples)
+}
+
+func TestEncoderWithWindows1252Charset(t *testing.T) {
+	a := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithCharset(csv.Windows1252), csv.WithBOM(true))
+	err := encoder.Encode(NoTagStruct{ID: 1, Name: "José €"})
+	a.NilNow(err)
+	a.EqualNow([]byte("ID,Name\n1,Jos\xe9 \x80\n"), buf.Bytes())
+
+	buf.Reset()
+	encoder = csv.NewEncoder(buf, csv.WithCharset(csv.ISO88591))
+	err = encoder.Encode(NoTagStruct{ID: 1, Name: "José €"})
+	a.IsErrorNow(err, csv.ErrInvalidCharacter)
+}
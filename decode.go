@@ -5,9 +5,14 @@ import (
 	"encoding"
 	"encoding/csv"
 	"errors"
+	"fmt"
 	"io"
+	"iter"
+	"math/big"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,14 +25,90 @@ func Unmarshal(data []byte, v any) error {
 	e := NewDecoder(bytes.NewReader(data))
 	defer decoderPool.Put(e)
 
-	return e.unmarshal(v)
+	err := e.unmarshal(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// All returns an iterator over the records read from r, decoding each of them
+// into a value of type T.
+//
+// When a record cannot be decoded, the iterator yields the error with the zero
+// value of T and continues with the next record. Any other error, such as an
+// I/O error of r, ends the iteration after it has been yielded.
+func All[T any](r io.Reader, opts ...CSVOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		d := NewDecoder(r, opts...)
+		defer decoderPool.Put(d)
+
+		for {
+			var v T
+			err := d.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				var zero T
+				v = zero
+			}
+			if !yield(v, err) {
+				return
+			}
+			if err != nil && !isRecordError(err) {
+				return
+			}
+		}
+	}
+}
+
+// isRecordError reports whether the error is bound to a single record, so the
+// decoding can continue with the next record.
+func isRecordError(err error) bool {
+	var decodeErr *DecodeError
+	var parseErr *csv.ParseError
+	return errors.As(err, &decodeErr) || errors.As(err, &parseErr)
 }
 
+// A Decoder reads and decodes CSV records from an input stream.
+//
+// The header row is read once, on the first call to Decode, and reused by all
+// subsequent calls, so a Decoder can be used to decode one record at a time:
+//
+//	dec := csv.NewDecoder(r)
+//	for dec.More() {
+//		var row Record
+//		if err := dec.Decode(&row); err != nil {
+//			if err == io.EOF {
+//				break
+//			}
+//			return err
+//		}
+//		// use row
+//	}
 type Decoder struct {
-	reader     *csv.Reader
-	lastRecord []string
-	useLast    bool
-	noHeader   bool
+	reader        *csv.Reader
+	charset       *charsetReader
+	buffered      []*record
+	noHeader      bool
+	strictHeader  bool
+	forceHeader   bool
+	lenientHeader bool
+	nullString    string
+	defaults      *fieldMeta
+
+	collectErrors bool
+	maxErrors     int
+	errs          []*DecodeError
+	offset        int64
+	readErr       error
+
+	headerRead bool
+	header     []string
+	metaType   reflect.Type
+	meta       []*fieldMeta
+	missing    []*fieldMeta
 }
 
 var decoderPool sync.Pool = sync.Pool{
@@ -39,22 +120,86 @@ var decoderPool sync.Pool = sync.Pool{
 func NewDecoder(reader io.Reader, opts ...CSVOption) *Decoder {
 	builder := newCSVBuilder(opts...)
 
-	csvReader := csv.NewReader(reader)
+	charset := newCharsetReader(reader, builder.charset)
+	csvReader := csv.NewReader(charset)
 	v := decoderPool.Get()
 	if v == nil {
 		v = &Decoder{}
 	}
 	d := v.(*Decoder)
 	csvReader.Comma = builder.comma
-	d.reader = csvReader
-	d.noHeader = builder.noHeader
+	csvReader.Comment = builder.comment
+	csvReader.LazyQuotes = builder.lazyQuotes
+	csvReader.TrimLeadingSpace = builder.trimLeadingSpace
+	csvReader.FieldsPerRecord = builder.fieldsPerRecord
+	csvReader.ReuseRecord = builder.reuseRecord
+	*d = Decoder{
+		reader:        csvReader,
+		charset:       charset,
+		noHeader:      builder.noHeader,
+		strictHeader:  builder.strictHeader,
+		forceHeader:   builder.forceHeader,
+		lenientHeader: builder.lenientHeader,
+		nullString:    builder.nullString,
+		defaults:      builder.fieldDefaults,
+
+		collectErrors: builder.collectErrors,
+		maxErrors:     builder.maxErrors,
+	}
 	return d
 }
 
+// Decode reads the next CSV record(s) from its input and stores them in the
+// value pointed to by v.
+//
+// If v points to a slice, an array or a channel, Decode reads all remaining
+// records of the input into it. Otherwise, Decode reads exactly one record,
+// and it returns io.EOF if there are no more records to read.
+//
+// In the error collecting mode (see WithCollectErrors), invalid records are
+// skipped while decoding into a slice, an array or a channel, and Decode
+// returns a *DecodeErrors holding the errors of all skipped records.
 func (d *Decoder) Decode(v any) error {
 	return d.unmarshal(v)
 }
 
+// More reports whether there is another record in the input stream.
+//
+// If the header row has not been read yet, and the input contains only a
+// single line, More reports true and the following Decode call returns io.EOF
+// if that line turns out to be the header row.
+//
+// If reading the input fails, More reports true and the following Decode call
+// returns the error.
+func (d *Decoder) More() bool {
+	if d.readErr != nil {
+		return true
+	}
+
+	n := 1
+	if !d.headerRead && !d.noHeader {
+		n = 2
+	}
+
+	for len(d.buffered) < n {
+		r, err := d.read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				// keep the error for the next Decode call
+				d.readErr = err
+				return true
+			}
+			break
+		}
+		if d.reader.ReuseRecord {
+			r.fields = slices.Clone(r.fields)
+		}
+		d.buffered = append(d.buffered, r)
+	}
+
+	return len(d.buffered) > 0
+}
+
 func (d *Decoder) unmarshal(v any) error {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr {
@@ -63,18 +208,20 @@ func (d *Decoder) unmarshal(v any) error {
 
 	meta, err := d.getMetaFields(rv)
 	if err != nil {
-		if errors.Is(err, io.EOF) {
+		if errors.Is(err, io.EOF) && isCollectionType(rv.Type()) {
 			return nil
 		}
 		return err
 	}
 
-	for rv.Kind() == reflect.Pointer {
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
 		rv = rv.Elem()
 	}
 
-	switch rv.Kind() {
-	case reflect.Slice, reflect.Array:
+	d.errs = nil
+
+	switch kind := rv.Kind(); {
+	case (kind == reflect.Slice || kind == reflect.Array) && !isDynamicType(rv.Type()):
 		for i := 0; ; i++ {
 			if rv.Kind() == reflect.Array && i >= rv.Len() {
 				break
@@ -87,9 +234,17 @@ func (d *Decoder) unmarshal(v any) error {
 				elem = reflect.New(rv.Type().Elem())
 			}
 
-			ok, err := d.readRecord(meta, elem, i+1)
+			ok, err := d.readRecord(meta, elem)
 			if err != nil {
-				return err
+				if err := d.collectError(err); err != nil {
+					return err
+				}
+				// skip the invalid record
+				if i < rv.Len() {
+					elem.SetZero()
+				}
+				i--
+				continue
 			}
 			if !ok {
 				break
@@ -101,12 +256,15 @@ func (d *Decoder) unmarshal(v any) error {
 				rv.Set(reflect.Append(rv, elem.Elem()))
 			}
 		}
-	case reflect.Chan:
-		for i := 0; ; i++ {
+	case kind == reflect.Chan:
+		for {
 			elem := reflect.New(rv.Type().Elem()).Elem()
-			ok, err := d.readRecord(meta, elem, i+1)
+			ok, err := d.readRecord(meta, elem)
 			if err != nil {
-				return err
+				if err := d.collectError(err); err != nil {
+					return err
+				}
+				continue
 			}
 			if !ok {
 				break
@@ -114,34 +272,153 @@ func (d *Decoder) unmarshal(v any) error {
 			rv.Send(elem)
 		}
 	default:
-		_, err := d.readRecord(meta, rv, 1)
+		ok, err := d.readRecord(meta, rv)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return io.EOF
+		}
+	}
+
+	if len(d.errs) > 0 {
+		return newDecodeErrors(d.errs)
+	}
+
+	return nil
+}
+
+// collectError collects the error of a record in the error collecting mode,
+// and returns nil if the decoding can continue with the next record.
+func (d *Decoder) collectError(err error) error {
+	if !d.collectErrors {
 		return err
 	}
 
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return err
+		}
+		decodeErr = newDecodeError(parseErr.Line, parseErr.Column, d.offset, "", "", parseErr.Err)
+	}
+
+	d.errs = append(d.errs, decodeErr)
+	if d.maxErrors > 0 && len(d.errs) >= d.maxErrors {
+		return newDecodeErrors(d.errs)
+	}
+
 	return nil
 }
 
 func (d *Decoder) getMetaFields(rv reflect.Value) ([]*fieldMeta, error) {
+	ty, err := getValueType(rv)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.metaType == ty {
+		return d.meta, nil
+	}
+
+	if isDynamicType(ty) {
+		// the first line is always the header for the maps and the string
+		// slices
+		if !d.noHeader && !d.headerRead {
+			r, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			d.header = slices.Clone(r.fields)
+			d.headerRead = true
+		}
+
+		d.metaType, d.meta, d.missing = ty, nil, nil
+		return nil, nil
+	}
+
 	meta, err := reflectMetadata(rv)
 	if err != nil {
 		return nil, err
 	}
+	meta = mergeMetaFields(meta, d.defaults)
 
 	if d.noHeader {
+		meta, err = positionalMetaFields(meta)
+		if err != nil {
+			return nil, err
+		}
+		d.metaType, d.meta, d.missing = ty, meta, nil
 		return meta, nil
 	}
 
-	header, err := d.readLine()
-	if err != nil {
-		return nil, err
+	if !d.headerRead {
+		r, err := d.readLine()
+		if err != nil {
+			return nil, err
+		}
+		d.header = slices.Clone(r.fields)
+		d.headerRead = true
+
+		if _, matched := d.orderMetaFields(meta, d.header); !matched && !d.strictHeader && !d.forceHeader {
+			// skip header parse if no field matched, and reuse the line as a
+			// record
+			d.unreadLine(r)
+			d.header = nil
+		}
+	}
+
+	orderedMeta := meta
+	if d.header == nil {
+		// the first line is a record, so the fields are laid out by position
+		orderedMeta, err = positionalMetaFields(meta)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		orderedMeta, _ = d.orderMetaFields(meta, d.header)
+		if err := checkDuplicateColumns(orderedMeta, d.header); err != nil {
+			return nil, err
+		}
+		if d.strictHeader {
+			if err := checkHeader(meta, orderedMeta, d.header); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	d.metaType, d.meta = ty, orderedMeta
+	d.missing = nil
+	if d.header != nil {
+		d.missing = missingMetaFields(meta, orderedMeta)
 	}
+	return orderedMeta, nil
+}
+
+// isCollectionType reports whether the type (or the type it points to) is a
+// slice, an array or a channel.
+func isCollectionType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Chan:
+		return !isDynamicType(t)
+	default:
+		return false
+	}
+}
 
-	// reorder meta according to header
+// orderMetaFields reorders the fields metadata according to the header, and
+// reports whether any column of the header matched a field.
+func (d *Decoder) orderMetaFields(meta []*fieldMeta, header []string) ([]*fieldMeta, bool) {
 	orderedMeta := make([]*fieldMeta, len(header))
 	matched := false
 	for i, colName := range header {
 		for _, m := range meta {
-			if m.Name == colName {
+			if d.matchColumn(m, colName) {
 				orderedMeta[i] = m
 				matched = true
 				break
@@ -149,33 +426,143 @@ func (d *Decoder) getMetaFields(rv reflect.Value) ([]*fieldMeta, error) {
 		}
 	}
 
-	// skip header parse if no field matched
-	if !matched {
-		// mark the last record to reuse
-		d.useLast = true
-		return meta, nil
+	return orderedMeta, matched
+}
+
+// matchColumn reports whether the column name of the header matches the name
+// or any alias of the field.
+func (d *Decoder) matchColumn(m *fieldMeta, colName string) bool {
+	if d.lenientHeader {
+		colName = strings.TrimSpace(colName)
 	}
 
-	return orderedMeta, nil
+	for i := -1; i < len(m.Aliases); i++ {
+		name := m.Name
+		if i >= 0 {
+			name = m.Aliases[i]
+		}
+
+		if name == colName || (d.lenientHeader && strings.EqualFold(name, colName)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkDuplicateColumns returns ErrDuplicateColumn if two columns of the
+// header match the same field.
+func checkDuplicateColumns(orderedMeta []*fieldMeta, header []string) error {
+	for i, m := range orderedMeta {
+		if m == nil {
+			continue
+		}
+		if j := slices.Index(orderedMeta[i+1:], m); j >= 0 {
+			return fmt.Errorf("%w: columns %q and %q match field %s",
+				ErrDuplicateColumn, header[i], header[i+1+j], m.Name)
+		}
+	}
+
+	return nil
+}
+
+// checkHeader returns a HeaderError if any column of the header has no
+// matching field, or any field has no matching column.
+func checkHeader(meta, orderedMeta []*fieldMeta, header []string) error {
+	unknown := make([]string, 0)
+	for i, m := range orderedMeta {
+		if m == nil {
+			unknown = append(unknown, header[i])
+		}
+	}
+
+	missing := make([]string, 0)
+	for _, m := range missingMetaFields(meta, orderedMeta) {
+		missing = append(missing, m.Name)
+	}
+
+	if len(unknown) > 0 || len(missing) > 0 {
+		return newHeaderError(unknown, missing)
+	}
+	return nil
 }
 
-func (d *Decoder) readLine() ([]string, error) {
-	if d.useLast {
-		d.useLast = false
-		return d.lastRecord, nil
+// missingMetaFields returns the fields metadata that are not in the ordered
+// fields metadata.
+func missingMetaFields(meta, orderedMeta []*fieldMeta) []*fieldMeta {
+	var missing []*fieldMeta
+	for _, m := range meta {
+		if !slices.Contains(orderedMeta, m) {
+			missing = append(missing, m)
+		}
 	}
+	return missing
+}
+
+// record is a CSV record with the positions of its fields in the input.
+type record struct {
+	fields []string
+	// lines and columns of the start of each field
+	lines   []int
+	columns []int
+	// byte offset of the input before reading the record
+	offset int64
+}
 
-	records, err := d.reader.Read()
+// position returns the line and the column of the field at index i. For a
+// field out of the record, it returns the line of the record and column 0.
+func (r *record) position(i int) (int, int) {
+	if i < 0 || i >= len(r.fields) {
+		if len(r.lines) == 0 {
+			return 0, 0
+		}
+		return r.lines[0], 0
+	}
+	return r.lines[i], r.columns[i]
+}
+
+// read reads a record from the underlying reader.
+func (d *Decoder) read() (*record, error) {
+	d.offset = d.reader.InputOffset() + int64(d.charset.skipped)
+	fields, err := d.reader.Read()
 	if err != nil {
 		return nil, err
 	}
 
-	d.lastRecord = records
-	return records, nil
+	r := &record{
+		fields:  fields,
+		lines:   make([]int, len(fields)),
+		columns: make([]int, len(fields)),
+		offset:  d.offset,
+	}
+	for i := range fields {
+		r.lines[i], r.columns[i] = d.reader.FieldPos(i)
+	}
+
+	return r, nil
+}
+
+func (d *Decoder) readLine() (*record, error) {
+	if len(d.buffered) > 0 {
+		r := d.buffered[0]
+		d.buffered = d.buffered[1:]
+		return r, nil
+	}
+	if d.readErr != nil {
+		err := d.readErr
+		d.readErr = nil
+		return nil, err
+	}
+
+	return d.read()
 }
 
-func (d *Decoder) readRecord(meta []*fieldMeta, v reflect.Value, line int) (bool, error) {
-	record, err := d.readLine()
+func (d *Decoder) unreadLine(r *record) {
+	d.buffered = append([]*record{r}, d.buffered...)
+}
+
+func (d *Decoder) readRecord(meta []*fieldMeta, v reflect.Value) (bool, error) {
+	r, err := d.readLine()
 	if err != nil {
 		if errors.Is(err, io.EOF) {
 			return false, nil
@@ -193,25 +580,100 @@ func (d *Decoder) readRecord(meta []*fieldMeta, v reflect.Value, line int) (bool
 		v = v.Elem()
 	}
 
-	for i, col := range record {
-		if i >= len(meta) {
-			break
+	switch v.Kind() {
+	case reflect.Map:
+		d.setMapRecord(r, v)
+		return true, nil
+	case reflect.Slice:
+		fields := r.fields
+		if d.reader.ReuseRecord {
+			fields = slices.Clone(fields)
 		}
+		v.Set(reflect.ValueOf(fields).Convert(v.Type()))
+		return true, nil
+	}
 
-		m := meta[i]
+	for i, m := range meta {
 		if m == nil {
 			continue
 		}
 
-		fv := v.Field(m.Index)
-		if err := d.marshalValue(col, fv, m); err != nil {
-			return false, newDecodeError(line, i+1, m.Name, col, err)
+		col, ok := "", i < len(r.fields)
+		if ok {
+			col = r.fields[i]
+		}
+		if err := d.decodeField(col, ok, v, m); err != nil {
+			line, column := r.position(i)
+			return false, newDecodeError(line, column, r.offset, m.Name, col, err)
+		}
+	}
+
+	// fields without column in the header
+	for _, m := range d.missing {
+		if err := d.decodeField("", false, v, m); err != nil {
+			line, column := r.position(-1)
+			return false, newDecodeError(line, column, r.offset, m.Name, "", err)
 		}
 	}
 
 	return true, nil
 }
 
+// setMapRecord sets the cells of the record into the map, keyed by the column
+// names of the header, or by the column indexes if there is no header.
+func (d *Decoder) setMapRecord(r *record, v reflect.Value) {
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(r.fields)))
+	}
+
+	elemType := v.Type().Elem()
+	for i, col := range r.fields {
+		key := strconv.Itoa(i)
+		if i < len(d.header) {
+			key = d.header[i]
+		}
+
+		val := reflect.Zero(elemType)
+		if d.nullString == "" || col != d.nullString {
+			val = reflect.ValueOf(col).Convert(elemType)
+		}
+		v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), val)
+	}
+}
+
+// decodeField decodes the cell into the field of the struct. The ok parameter
+// reports whether the record has the cell of the field.
+func (d *Decoder) decodeField(col string, ok bool, v reflect.Value, m *fieldMeta) error {
+	if col == "" && m.Default != "" {
+		col, ok = m.Default, true
+	}
+	if col == "" && m.Required {
+		return ErrRequiredField
+	}
+	if !ok {
+		return nil
+	}
+
+	fv, err := fieldByIndex(v, m.Index)
+	if err != nil {
+		return err
+	}
+	if d.nullString != "" && col == d.nullString {
+		if m.Required {
+			return ErrRequiredField
+		}
+		fv.SetZero()
+		return nil
+	}
+	if col == "" && m.OmitEmpty && fv.Kind() != reflect.Pointer {
+		// the empty cell written for the zero value of the omitempty field
+		fv.SetZero()
+		return nil
+	}
+
+	return d.marshalValue(col, fv, m)
+}
+
 var (
 	unmarshalerType     = reflect.TypeFor[Unmarshaler]()
 	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
@@ -242,18 +704,30 @@ func (d *Decoder) marshalValue(col string, v reflect.Value, meta *fieldMeta) err
 	case reflect.Bool:
 		return boolDecoder(col, v, meta)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == durationType {
+			return durationDecoder(col, v, meta)
+		}
 		return intDecoder(col, v, meta)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return uintDecoder(col, v, meta)
 	case reflect.Float32, reflect.Float64:
 		return floatDecoder(col, v, meta)
+	case reflect.Complex64, reflect.Complex128:
+		return complexDecoder(col, v, meta)
 	case reflect.String:
 		return stringDecoder(col, v, meta)
 	case reflect.Pointer:
 		return d.newPointerDecoder(col, v, meta)
 	case reflect.Struct:
-		if t.ConvertibleTo(timeType) {
+		switch {
+		case t.ConvertibleTo(timeType):
 			return timeDecoder(col, v, meta)
+		case t == bigIntType && v.CanAddr():
+			return bigIntDecoder(col, v, meta)
+		case t == bigFloatType && v.CanAddr():
+			return bigFloatDecoder(col, v, meta)
+		case t == bigRatType && v.CanAddr():
+			return bigRatDecoder(col, v, meta)
 		}
 	}
 
@@ -264,38 +738,89 @@ func (d *Decoder) marshalValue(col string, v reflect.Value, meta *fieldMeta) err
 	return unsupportedDecoder(col, v, meta)
 }
 
-func boolDecoder(s string, v reflect.Value, _ *fieldMeta) error {
-	switch s {
-	case "true", "1":
+func boolDecoder(s string, v reflect.Value, m *fieldMeta) error {
+	// the unset token falls back to the default tokens
+	isTrue := s == "1" || strings.EqualFold(s, "true")
+	if m.TrueToken != "" {
+		isTrue = strings.EqualFold(s, m.TrueToken)
+	}
+	isFalse := s == "0" || strings.EqualFold(s, "false")
+	if m.FalseToken != "" {
+		isFalse = strings.EqualFold(s, m.FalseToken)
+	}
+
+	switch {
+	case isTrue:
 		v.SetBool(true)
-	case "false", "0":
+	case isFalse:
 		v.SetBool(false)
+	case m.StrictBool:
+		return &strconv.NumError{Func: "ParseBool", Num: s, Err: strconv.ErrSyntax}
 	default:
 		v.SetBool(false)
 	}
 	return nil
 }
 
-func intDecoder(s string, v reflect.Value, _ *fieldMeta) error {
-	intVal, err := strconv.ParseInt(s, 10, 64)
+func intDecoder(s string, v reflect.Value, m *fieldMeta) error {
+	s = normalizeInt(s, m)
+	intVal, err := strconv.ParseInt(s, m.Base, 64)
 	if err != nil {
 		return err
 	}
+	if v.OverflowInt(intVal) {
+		return &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrRange}
+	}
 	v.SetInt(intVal)
 	return nil
 }
 
-func uintDecoder(s string, v reflect.Value, _ *fieldMeta) error {
-	uintVal, err := strconv.ParseUint(s, 10, 64)
+func uintDecoder(s string, v reflect.Value, m *fieldMeta) error {
+	s = normalizeInt(s, m)
+	uintVal, err := strconv.ParseUint(s, m.Base, 64)
 	if err != nil {
 		return err
 	}
+	if v.OverflowUint(uintVal) {
+		return &strconv.NumError{Func: "ParseUint", Num: s, Err: strconv.ErrRange}
+	}
 	v.SetUint(uintVal)
 	return nil
 }
 
-func floatDecoder(s string, v reflect.Value, _ *fieldMeta) error {
-	floatVal, err := strconv.ParseFloat(s, 64)
+// normalizeInt prepares the integer to parse in the base of the field. The
+// number format applies to the base 10 only, and the 0x, 0o and 0b prefixes
+// are accepted for the bases 16, 8 and 2.
+func normalizeInt(s string, m *fieldMeta) string {
+	var prefix string
+	switch m.Base {
+	case 10:
+		return m.Number.normalize(s)
+	case 16:
+		prefix = "0x"
+	case 8:
+		prefix = "0o"
+	case 2:
+		prefix = "0b"
+	default:
+		return s
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	if len(s) > len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		s = s[len(prefix):]
+	}
+	return sign + s
+}
+
+func floatDecoder(s string, v reflect.Value, m *fieldMeta) error {
+	if isDecimalFormat(m.FloatFmt) {
+		s = m.Number.normalize(s)
+	}
+	floatVal, err := strconv.ParseFloat(s, v.Type().Bits())
 	if err != nil {
 		return err
 	}
@@ -303,6 +828,45 @@ func floatDecoder(s string, v reflect.Value, _ *fieldMeta) error {
 	return nil
 }
 
+func complexDecoder(s string, v reflect.Value, _ *fieldMeta) error {
+	complexVal, err := strconv.ParseComplex(s, v.Type().Bits())
+	if err != nil {
+		return err
+	}
+	v.SetComplex(complexVal)
+	return nil
+}
+
+func bigIntDecoder(s string, v reflect.Value, m *fieldMeta) error {
+	s = normalizeInt(s, m)
+	if _, ok := v.Addr().Interface().(*big.Int).SetString(s, m.Base); !ok {
+		return &strconv.NumError{Func: "SetString", Num: s, Err: strconv.ErrSyntax}
+	}
+	return nil
+}
+
+func bigFloatDecoder(s string, v reflect.Value, m *fieldMeta) error {
+	if isDecimalFormat(m.FloatFmt) {
+		s = m.Number.normalize(s)
+	}
+	f := v.Addr().Interface().(*big.Float)
+	if m.Bits > 0 {
+		f.SetPrec(m.Bits)
+	}
+	if _, ok := f.SetString(s); !ok {
+		return &strconv.NumError{Func: "SetString", Num: s, Err: strconv.ErrSyntax}
+	}
+	return nil
+}
+
+func bigRatDecoder(s string, v reflect.Value, m *fieldMeta) error {
+	s = m.Number.normalize(s)
+	if _, ok := v.Addr().Interface().(*big.Rat).SetString(s); !ok {
+		return &strconv.NumError{Func: "SetString", Num: s, Err: strconv.ErrSyntax}
+	}
+	return nil
+}
+
 func stringDecoder(s string, v reflect.Value, _ *fieldMeta) error {
 	v.SetString(s)
 	return nil
@@ -313,16 +877,54 @@ func timeDecoder(s string, v reflect.Value, m *fieldMeta) error {
 		return nil
 	}
 
-	layout := time.RFC3339Nano
+	loc := time.UTC
+	if m.Location != nil {
+		loc = m.Location
+	}
+
+	layouts := []string{time.RFC3339Nano}
 	if m.Format != "" {
-		layout = m.Format
+		layouts = strings.Split(m.Format, "|")
 	}
 
-	tm, err := time.Parse(layout, s)
+	var firstErr error
+	for _, layout := range layouts {
+		tm, err := parseTime(s, layout, loc)
+		if err == nil {
+			v.Set(reflect.ValueOf(tm).Convert(v.Type()))
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// parseTime parses the time by the layout, or as the Unix time for the unix
+// and unixms layouts. The time without time zone is in the location.
+func parseTime(s, layout string, loc *time.Location) (time.Time, error) {
+	switch layout {
+	case "unix", "unixms":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if layout == "unixms" {
+			return time.UnixMilli(n).In(loc), nil
+		}
+		return time.Unix(n, 0).In(loc), nil
+	default:
+		return time.ParseInLocation(layout, s, loc)
+	}
+}
+
+func durationDecoder(s string, v reflect.Value, _ *fieldMeta) error {
+	d, err := time.ParseDuration(s)
 	if err != nil {
 		return err
 	}
-	v.Set(reflect.ValueOf(tm))
+	v.SetInt(int64(d))
 	return nil
 }
 
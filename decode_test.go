@@ -2,7 +2,11 @@ package csv_test
 
 import (
 	"bytes"
+	gocsv "encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"testing"
 	"time"
 
@@ -50,12 +54,26 @@ func TestDecodeStructWithErrorValue(t *testing.T) {
 	err := csv.Unmarshal([]byte(data), &sample)
 	a.NotNilNow(err)
 	decodeErr := err.(*csv.DecodeError)
-	a.EqualNow(decodeErr.Row(), 1)
-	a.EqualNow(decodeErr.Col(), 3)
+	a.EqualNow(decodeErr.Row(), 2)
+	a.EqualNow(decodeErr.Col(), 12)
+	a.EqualNow(decodeErr.Offset(), int64(30))
 	a.EqualNow(decodeErr.Field(), "age")
 	a.EqualNow(decodeErr.Value(), "thirty")
 }
 
+func TestDecodeStructWithErrorValueAfterMultilineField(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,\"John\nDoe\",30,5500,true\n\n2,Jane Smith,25,abc,false\n"
+	var samples []SampleStruct
+	err := csv.Unmarshal([]byte(data), &samples)
+	a.NotNilNow(err)
+	decodeErr := err.(*csv.DecodeError)
+	a.EqualNow(decodeErr.Row(), 5)
+	a.EqualNow(decodeErr.Col(), 17)
+	a.EqualNow(decodeErr.Field(), "salary")
+	a.EqualNow(decodeErr.Value(), "abc")
+}
+
 func TestDecodeStructWithInvalidBoolValue(t *testing.T) {
 	a := assert.New(t)
 	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,unknown\n"
@@ -72,6 +90,159 @@ func TestDecodeStructWithInvalidBoolValue(t *testing.T) {
 	a.EqualNow(expected, sample)
 }
 
+func TestDecodeStructWithUpperCaseBoolValue(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,TRUE\n"
+	var sample SampleStruct
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.NilNow(err)
+	a.TrueNow(sample.IsManager)
+}
+
+func TestDecoderWithStrictBoolOption(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,unknown\n"
+	var sample SampleStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictBool(true))
+	err := decoder.Decode(&sample)
+	a.IsErrorNow(err, strconv.ErrSyntax)
+	var decodeErr *csv.DecodeError
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("is_manager", decodeErr.Field())
+
+	data = "id,name,age,salary,is_manager\n1,John Doe,30,5500,\n"
+	decoder = csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictBool(true))
+	err = decoder.Decode(&sample)
+	a.IsErrorNow(err, strconv.ErrSyntax)
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("is_manager", decodeErr.Field())
+	a.EqualNow("", decodeErr.Value())
+}
+
+type BoolTokenStruct struct {
+	Active   bool  `csv:"active,true=Y,false=N"`
+	Verified *bool `csv:"verified"`
+}
+
+func TestDecodeStructWithBoolTokens(t *testing.T) {
+	a := assert.New(t)
+	data := "active,verified\ny,Yes\nN,no\n"
+	var samples []BoolTokenStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithBoolTokens("yes", "no"), csv.WithStrictBool(true))
+	err := decoder.Decode(&samples)
+	a.NilNow(err)
+	yes, no := true, false
+	a.DeepEqualNow([]BoolTokenStruct{{Active: true, Verified: &yes}, {Active: false, Verified: &no}}, samples)
+
+	decoder = csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictBool(true))
+	err = decoder.Decode(&samples)
+	a.NotNilNow(err)
+}
+
+type LocaleNumberStruct struct {
+	Price    float64 `csv:"price,decimal=comma,group=dot,currency=€"`
+	Quantity int     `csv:"quantity"`
+	Rate     float64 `csv:"rate,group=none,percent"`
+}
+
+func TestDecodeStructWithNumberFormat(t *testing.T) {
+	a := assert.New(t)
+	data := "price,quantity,rate\n\"1.234,56 €\",\"1,000\",12.5%\n\"-€5,5\",\"-2,500\",0%\n"
+	var samples []LocaleNumberStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithNumberFormat(csv.NumberFormat{Group: ','}))
+	err := decoder.Decode(&samples)
+	a.NilNow(err)
+	a.DeepEqualNow([]LocaleNumberStruct{
+		{Price: 1234.56, Quantity: 1000, Rate: 12.5},
+		{Price: -5.5, Quantity: -2500, Rate: 0},
+	}, samples)
+
+	err = csv.Unmarshal([]byte("price,quantity,rate\n1,\"1,000\",1\n"), &samples)
+	a.NotNilNow(err)
+}
+
+func TestDecodeFloat32Field(t *testing.T) {
+	a := assert.New(t)
+	var samples []FloatFormatStruct
+
+	err := csv.Unmarshal([]byte("ratio\n0.1\n"), &samples)
+	a.NilNow(err)
+	a.EqualNow(float32(0.1), samples[0].Ratio)
+
+	err = csv.Unmarshal([]byte("ratio\n1e39\n"), &samples)
+	a.IsErrorNow(err, strconv.ErrRange)
+}
+
+type IntegerBaseStruct struct {
+	ID    uint32 `csv:"id,base=16"`
+	Flags int16  `csv:"flags,base=0"`
+	Level int8   `csv:"level"`
+}
+
+func TestDecodeStructWithIntegerBase(t *testing.T) {
+	a := assert.New(t)
+	data := "id,flags,level\nff,0x10,-5\n0XDEADBEEF,0b101,127\n-1,-0o17,0\n"
+	var samples []IntegerBaseStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithCollectErrors(0))
+	err := decoder.Decode(&samples)
+	a.DeepEqualNow([]IntegerBaseStruct{
+		{ID: 0xff, Flags: 16, Level: -5},
+		{ID: 0xdeadbeef, Flags: 5, Level: 127},
+	}, samples)
+	var decodeErr *csv.DecodeError
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("id", decodeErr.Field())
+}
+
+func TestDecodeStructWithIntegerOverflow(t *testing.T) {
+	a := assert.New(t)
+	var samples []IntegerBaseStruct
+
+	err := csv.Unmarshal([]byte("id,flags,level\n1,1,300\n"), &samples)
+	var decodeErr *csv.DecodeError
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("level", decodeErr.Field())
+	a.EqualNow("300", decodeErr.Value())
+	a.IsErrorNow(err, strconv.ErrRange)
+
+	err = csv.Unmarshal([]byte("id,flags,level\n100000000,1,1\n"), &samples)
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("id", decodeErr.Field())
+	a.IsErrorNow(err, strconv.ErrRange)
+}
+
+func TestDecodeStructWithHalfBoolTokens(t *testing.T) {
+	a := assert.New(t)
+	data := "id,active\n1,y\n2,false\n3,0\n"
+	var samples []HalfBoolTokenStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictBool(true))
+	err := decoder.Decode(&samples)
+	a.NilNow(err)
+	a.DeepEqualNow([]HalfBoolTokenStruct{{ID: 1, Active: true}, {ID: 2}, {ID: 3}}, samples)
+
+	decoder = csv.NewDecoder(bytes.NewReader([]byte("id,active\n1,true\n")), csv.WithStrictBool(true))
+	err = decoder.Decode(&samples)
+	a.IsErrorNow(err, strconv.ErrSyntax)
+}
+
+func TestDecodeStructWithOmitEmptyFields(t *testing.T) {
+	a := assert.New(t)
+	samples := []OmitEmptyStruct{{ID: 1}, {ID: 2, Age: 30, IsManager: true}}
+
+	data, err := csv.Marshal(samples)
+	a.NilNow(err)
+
+	var decoded []OmitEmptyStruct
+	err = csv.Unmarshal(data, &decoded)
+	a.NilNow(err)
+	a.DeepEqualNow(samples, decoded)
+}
+
 func TestDecodeStructWithoutHeader(t *testing.T) {
 	a := assert.New(t)
 	data := "1,John Doe,30,5500,true\n"
@@ -271,6 +442,30 @@ func TestDecodePointerFieldsStructWithEmptyField(t *testing.T) {
 	a.DeepEqualNow(expected, sample)
 }
 
+func TestDecoderWithNullStringOption(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,NULL,NULL,,NULL\n"
+	var sample SimplePointerStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithNullString("NULL"))
+	err := decoder.Decode(&sample)
+	a.NilNow(err)
+	id := 1
+	expected := SimplePointerStruct{ID: &id}
+	a.DeepEqualNow(expected, sample)
+}
+
+func TestDecoderWithNullStringOptionNonPointer(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,NULL,NULL,5500,true\n"
+	sample := SampleStruct{Name: "Unknown", Age: 10}
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithNullString("NULL"))
+	err := decoder.Decode(&sample)
+	a.NilNow(err)
+	a.EqualNow(SampleStruct{ID: 1, Salary: 5500, IsManager: true}, sample)
+}
+
 func (m *MarshalableStruct) UnmarshalCSV(b []byte) error {
 	_, err := fmt.Sscanf(string(b), "%s (%d)", &m.Country, &m.ZipCode)
 	return err
@@ -411,6 +606,279 @@ func TestDecodeTimeStruct(t *testing.T) {
 	a.DeepEqualNow(sample, expected)
 }
 
+func TestDecodeZonedTimeStruct(t *testing.T) {
+	a := assert.New(t)
+	data := "timeout,local,unix,unix_ms,deadline\n" +
+		"1m30s,2025-10-01 13:30,1759318200,1759318200250,2025-10-01T13:30:00+02:00\n" +
+		"1h,2025-01-15,0,0,2025-10-01T11:30:00Z\n"
+	var samples []ZonedTimeStruct
+
+	err := csv.Unmarshal([]byte(data), &samples)
+	a.NilNow(err)
+	a.EqualNow(90*time.Second, samples[0].Timeout)
+	a.TrueNow(samples[0].Local.Equal(time.Date(2025, 10, 1, 11, 30, 0, 0, time.UTC)))
+	a.EqualNow("Europe/Berlin", samples[0].Local.Location().String())
+	a.TrueNow(samples[0].Unix.Equal(time.Date(2025, 10, 1, 11, 30, 0, 0, time.UTC)))
+	a.TrueNow(samples[0].UnixMs.Equal(time.Date(2025, 10, 1, 11, 30, 0, 250000000, time.UTC)))
+	a.TrueNow(samples[0].Deadline.Equal(samples[1].Deadline))
+	a.EqualNow(time.Hour, samples[1].Timeout)
+	a.TrueNow(samples[1].Local.Equal(time.Date(2025, 1, 14, 23, 0, 0, 0, time.UTC)))
+	a.TrueNow(samples[1].Unix.Equal(time.Unix(0, 0)))
+
+	err = csv.Unmarshal([]byte("timeout\n90\n"), &samples)
+	a.NotNilNow(err)
+	err = csv.Unmarshal([]byte("local\n01/15/2025\n"), &samples)
+	a.NotNilNow(err)
+}
+
+func TestDecodeBigNumberStruct(t *testing.T) {
+	a := assert.New(t)
+	data := "count,amount,ratio,share,signal,impulse\n" +
+		"\"123,456,789,012,345,678,901,234,567,890\",0.1,1/3,0.6667,(0.1+2i),(1.5e+03-0.5i)\n"
+	var sample BigNumberStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.NilNow(err)
+	a.EqualNow("123456789012345678901234567890", sample.Count.String())
+	a.EqualNow(uint(128), sample.Amount.Prec())
+	a.EqualNow("0.10000000000000000000000000000000000000", sample.Amount.Text('f', 38))
+	a.EqualNow("1/3", sample.Ratio.RatString())
+	a.EqualNow("6667/10000", sample.Share.RatString())
+	a.EqualNow(complex64(complex(0.1, 2)), sample.Signal)
+	a.EqualNow(complex(1500, -0.5), *sample.Impulse)
+
+	err = csv.Unmarshal([]byte("count\n12abc\n"), &sample)
+	var decodeErr *csv.DecodeError
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("count", decodeErr.Field())
+	a.IsErrorNow(err, strconv.ErrSyntax)
+}
+
+type UnknownZoneStruct struct {
+	At time.Time `csv:"at,format=2006-01-02 15:04,tz=Europe/Berln"`
+}
+
+func TestDecodeStructWithUnknownTimeZone(t *testing.T) {
+	a := assert.New(t)
+	var samples []UnknownZoneStruct
+
+	err := csv.Unmarshal([]byte("at\n2025-10-01 13:30\n"), &samples)
+	a.IsErrorNow(err, csv.ErrInvalidTag)
+
+	_, err = csv.Marshal([]UnknownZoneStruct{{}})
+	a.IsErrorNow(err, csv.ErrInvalidTag)
+}
+
+func TestDecodeStructWithInvalidTagOptions(t *testing.T) {
+	a := assert.New(t)
+	samples := []any{
+		&struct {
+			ID int `csv:"id,base=99"`
+		}{},
+		&struct {
+			ID int `csv:"id,base=x"`
+		}{},
+		&struct {
+			ID int `csv:"id,index=abc"`
+		}{},
+		&struct {
+			Rate float64 `csv:"rate,prec=-1"`
+		}{},
+		&struct {
+			Rate float64 `csv:"rate,fmt=z"`
+		}{},
+		&struct {
+			Rate float64 `csv:"rate,decimal=none"`
+		}{},
+		&struct {
+			Rate float64 `csv:"rate,group=dots"`
+		}{},
+		&struct {
+			Rate float64 `csv:"rate,bits=many"`
+		}{},
+	}
+
+	for _, sample := range samples {
+		err := csv.Unmarshal([]byte("1\n"), sample)
+		a.IsErrorNow(err, csv.ErrInvalidTag)
+
+		_, err = csv.Marshal(sample)
+		a.IsErrorNow(err, csv.ErrInvalidTag)
+	}
+}
+
+type RequiredDefaultStruct struct {
+	ID     int    `csv:"id,required"`
+	Name   string `csv:"name,required"`
+	Status string `csv:"status,default=active"`
+	Level  int    `csv:"level,default=1"`
+}
+
+func TestDecodeStructWithDefaultValue(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,status,level\n1,John Doe,,\n2,Jane Smith,inactive,3\n"
+	var samples []RequiredDefaultStruct
+
+	err := csv.Unmarshal([]byte(data), &samples)
+	a.NilNow(err)
+	expected := []RequiredDefaultStruct{
+		{ID: 1, Name: "John Doe", Status: "active", Level: 1},
+		{ID: 2, Name: "Jane Smith", Status: "inactive", Level: 3},
+	}
+	a.EqualNow(expected, samples)
+}
+
+func TestDecodeStructWithDefaultValueMissingColumn(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name\n1,John Doe\n"
+	var sample RequiredDefaultStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.NilNow(err)
+	a.EqualNow(RequiredDefaultStruct{ID: 1, Name: "John Doe", Status: "active", Level: 1}, sample)
+}
+
+func TestDecodeStructWithEmptyRequiredField(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,status,level\n1,,active,1\n"
+	var sample RequiredDefaultStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.IsErrorNow(err, csv.ErrRequiredField)
+	var decodeErr *csv.DecodeError
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("name", decodeErr.Field())
+}
+
+func TestDecodeStructWithMissingRequiredColumn(t *testing.T) {
+	a := assert.New(t)
+	data := "id,status\n1,active\n"
+	var sample RequiredDefaultStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.IsErrorNow(err, csv.ErrRequiredField)
+	var decodeErr *csv.DecodeError
+	a.TrueNow(errors.As(err, &decodeErr))
+	a.EqualNow("name", decodeErr.Field())
+}
+
+func TestDecodeStructWithEmbeddedStruct(t *testing.T) {
+	a := assert.New(t)
+	data := "id,note,created_at,updated_by,tag_name\n1,first,2025-10-01,admin,tag\n"
+	var sample EmbeddedStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.NilNow(err)
+	expected := EmbeddedStruct{
+		ID:    1,
+		Note:  "first",
+		Audit: Audit{CreatedAt: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), UpdatedBy: "admin"},
+		Tags:  &Tags{Name: "tag"},
+	}
+	a.DeepEqualNow(expected, sample)
+}
+
+func TestDecodeStructWithMissingEmbeddedPointerColumns(t *testing.T) {
+	a := assert.New(t)
+	data := "id,note\n1,first\n"
+	var sample EmbeddedStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.NilNow(err)
+	a.DeepEqualNow(EmbeddedStruct{ID: 1, Note: "first"}, sample)
+}
+
+func TestDecodeStructWithInlineFields(t *testing.T) {
+	a := assert.New(t)
+	data := "id,shipping.city,billing.street,billing.city,billing.geo.lat\n1,Shelbyville,1 Main St,Springfield,1.5\n"
+	var sample InlineStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.NilNow(err)
+	expected := InlineStruct{
+		ID:       1,
+		Billing:  Address{Street: "1 Main St", City: "Springfield", Geo: &Geo{Lat: 1.5}},
+		Shipping: &Address{City: "Shelbyville"},
+	}
+	a.DeepEqualNow(expected, sample)
+}
+
+func TestDecodeStringMapSlice(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,email\n1,John Doe,john@example.com\n2,Jane Smith,\n"
+	var rows []map[string]string
+
+	err := csv.Unmarshal([]byte(data), &rows)
+	a.NilNow(err)
+	expected := []map[string]string{
+		{"id": "1", "name": "John Doe", "email": "john@example.com"},
+		{"id": "2", "name": "Jane Smith", "email": ""},
+	}
+	a.DeepEqualNow(expected, rows)
+}
+
+func TestDecodeAnyMapSlice(t *testing.T) {
+	a := assert.New(t)
+	data := "id;name\n1;John Doe\n2;NULL\n"
+	var rows []map[string]any
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithComma(';'), csv.WithNullString("NULL"))
+	err := decoder.Decode(&rows)
+	a.NilNow(err)
+	expected := []map[string]any{
+		{"id": "1", "name": "John Doe"},
+		{"id": "2", "name": nil},
+	}
+	a.DeepEqualNow(expected, rows)
+}
+
+func TestDecodeMapWithoutHeader(t *testing.T) {
+	a := assert.New(t)
+	data := "1,John Doe\n"
+	var row map[string]string
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithNoHeader(true))
+	err := decoder.Decode(&row)
+	a.NilNow(err)
+	a.DeepEqualNow(map[string]string{"0": "1", "1": "John Doe"}, row)
+}
+
+func TestDecodeStringSlices(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name\n1,John Doe\n2,Jane Smith\n"
+	var rows [][]string
+
+	err := csv.Unmarshal([]byte(data), &rows)
+	a.NilNow(err)
+	a.DeepEqualNow([][]string{{"1", "John Doe"}, {"2", "Jane Smith"}}, rows)
+}
+
+func TestDecoderStreamingStringSlice(t *testing.T) {
+	a := assert.New(t)
+	data := "1,John Doe\n2,Jane Smith\n"
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithNoHeader(true))
+
+	rows := make([][]string, 0, 2)
+	for decoder.More() {
+		var row []string
+		a.NilNow(decoder.Decode(&row))
+		rows = append(rows, row)
+	}
+	a.DeepEqualNow([][]string{{"1", "John Doe"}, {"2", "Jane Smith"}}, rows)
+
+	var row []string
+	a.IsErrorNow(decoder.Decode(&row), io.EOF)
+}
+
+func TestDecodeUnsupportedMap(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name\n1,John Doe\n"
+	var rows []map[string]int
+
+	err := csv.Unmarshal([]byte(data), &rows)
+	a.IsErrorNow(err, csv.ErrInvalidType)
+}
+
 func TestDecoder_Decode(t *testing.T) {
 	a := assert.New(t)
 	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n"
@@ -466,6 +934,51 @@ func TestDecoderWithCSLFOption(t *testing.T) {
 	a.EqualNow(expected, sample)
 }
 
+func TestDecoderWithReaderOptions(t *testing.T) {
+	a := assert.New(t)
+	data := "# exported by vendor\nid, name, age\n1, John \"JD\" Doe, 30\n2, Jane Smith\n"
+	var samples []SampleStruct
+
+	decoder := csv.NewDecoder(
+		bytes.NewReader([]byte(data)),
+		csv.WithComment('#'),
+		csv.WithLazyQuotes(true),
+		csv.WithTrimLeadingSpace(true),
+		csv.WithFieldsPerRecord(-1),
+	)
+	err := decoder.Decode(&samples)
+	a.NilNow(err)
+	expected := []SampleStruct{
+		{ID: 1, Name: "John \"JD\" Doe", Age: 30},
+		{ID: 2, Name: "Jane Smith"},
+	}
+	a.EqualNow(expected, samples)
+}
+
+func TestDecoderWithReuseRecordOption(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name\n1,John Doe\n2,Jane Smith\n"
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithReuseRecord(true))
+	a.TrueNow(decoder.More())
+	var rows [][]string
+	err := decoder.Decode(&rows)
+	a.NilNow(err)
+	a.DeepEqualNow([][]string{{"1", "John Doe"}, {"2", "Jane Smith"}}, rows)
+}
+
+func TestDecodeWithBOM(t *testing.T) {
+	a := assert.New(t)
+	data := "\xEF\xBB\xBFid,name,age,salary,is_manager\n1,John Doe,30,5500,true\n2,Jane Smith,abc,3000,false\n"
+	var samples []SampleStruct
+
+	err := csv.Unmarshal([]byte(data), &samples)
+	a.NotNilNow(err)
+	decodeErr := err.(*csv.DecodeError)
+	a.EqualNow(int64(57), decodeErr.Offset())
+	a.EqualNow([]SampleStruct{{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}}, samples)
+}
+
 func TestDecoderWithNoHeaderOption(t *testing.T) {
 	a := assert.New(t)
 	data := "1,John Doe,30,5500,true\n"
@@ -485,6 +998,308 @@ func TestDecoderWithNoHeaderOption(t *testing.T) {
 	a.EqualNow(expected, sample)
 }
 
+func TestDecoderWithStrictHeaderOption(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n"
+	var sample SampleStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictHeader(true))
+	err := decoder.Decode(&sample)
+	a.NilNow(err)
+	a.EqualNow(SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}, sample)
+}
+
+func TestDecoderWithStrictHeaderOptionMismatch(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,email,phone\n1,John Doe,30,john@example.com,123\n"
+	var sample SampleStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictHeader(true))
+	err := decoder.Decode(&sample)
+	a.NotNilNow(err)
+
+	var headerErr *csv.HeaderError
+	a.TrueNow(errors.As(err, &headerErr))
+	a.EqualNow([]string{"email", "phone"}, headerErr.Unknown())
+	a.EqualNow([]string{"salary", "is_manager"}, headerErr.Missing())
+
+	decoder = csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictHeader(true), csv.WithStrictHeader(false))
+	err = decoder.Decode(&sample)
+	a.NilNow(err)
+}
+
+func TestDecoderWithStrictHeaderOptionUnmatchedHeader(t *testing.T) {
+	a := assert.New(t)
+	data := "1,John Doe,30,5500,true\n"
+	var sample SampleStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithStrictHeader(true))
+	err := decoder.Decode(&sample)
+	var headerErr *csv.HeaderError
+	a.TrueNow(errors.As(err, &headerErr))
+	a.EqualNow(5, len(headerErr.Unknown()))
+	a.EqualNow(5, len(headerErr.Missing()))
+}
+
+func TestDecoderWithCollectErrorsOption(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n" +
+		"1,John Doe,thirty,5500,true\n" +
+		"2,Jane Smith,25,3000,false\n" +
+		"3,Bob Brown,40,abc,false\n" +
+		"4,Alice\n" +
+		"5,Tom Green,35,4000,true\n"
+	var samples []SampleStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithCollectErrors(0))
+	err := decoder.Decode(&samples)
+	a.NotNilNow(err)
+
+	var decodeErrs *csv.DecodeErrors
+	a.TrueNow(errors.As(err, &decodeErrs))
+	a.EqualNow(3, len(decodeErrs.Errors()))
+	a.EqualNow("age", decodeErrs.Errors()[0].Field())
+	a.EqualNow("salary", decodeErrs.Errors()[1].Field())
+	a.IsErrorNow(decodeErrs.Errors()[2], gocsv.ErrFieldCount)
+
+	expected := []SampleStruct{
+		{ID: 2, Name: "Jane Smith", Age: 25, Salary: 3000, IsManager: false},
+		{ID: 5, Name: "Tom Green", Age: 35, Salary: 4000, IsManager: true},
+	}
+	a.EqualNow(expected, samples)
+}
+
+func TestDecoderWithCollectErrorsOptionLimit(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n" +
+		"1,John Doe,thirty,5500,true\n" +
+		"2,Jane Smith,25,3000,false\n" +
+		"3,Bob Brown,40,abc,false\n" +
+		"4,Tom Green,35,4000,true\n"
+	samplesChan := make(chan SampleStruct, 4)
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithCollectErrors(2))
+	err := decoder.Decode(&samplesChan)
+	close(samplesChan)
+
+	var decodeErrs *csv.DecodeErrors
+	a.TrueNow(errors.As(err, &decodeErrs))
+	a.EqualNow(2, len(decodeErrs.Errors()))
+
+	ids := make([]int, 0, 1)
+	for sample := range samplesChan {
+		ids = append(ids, sample.ID)
+	}
+	a.EqualNow([]int{2}, ids)
+}
+
+type AliasStruct struct {
+	ID    int    `csv:"id"`
+	Email string `csv:"email,alias=e-mail|mail"`
+}
+
+func TestDecodeStructWithAlias(t *testing.T) {
+	a := assert.New(t)
+	data := "id,e-mail\n1,john@example.com\n"
+	var sample AliasStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.NilNow(err)
+	a.EqualNow(AliasStruct{ID: 1, Email: "john@example.com"}, sample)
+}
+
+func TestDecodeStructWithDuplicateColumns(t *testing.T) {
+	a := assert.New(t)
+	data := "id,email,mail\n1,john@example.com,john@example.com\n"
+	var sample AliasStruct
+
+	err := csv.Unmarshal([]byte(data), &sample)
+	a.IsErrorNow(err, csv.ErrDuplicateColumn)
+}
+
+func TestDecoderWithLenientHeaderOption(t *testing.T) {
+	a := assert.New(t)
+	data := " ID , Mail \n1,john@example.com\n"
+	var sample AliasStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithLenientHeader(true))
+	err := decoder.Decode(&sample)
+	a.NilNow(err)
+	a.EqualNow(AliasStruct{ID: 1, Email: "john@example.com"}, sample)
+
+	decoder = csv.NewDecoder(bytes.NewReader([]byte(data)))
+	err = decoder.Decode(&sample)
+	a.NotNilNow(err)
+}
+
+func TestDecodeStructWithIndexedFieldsWithoutHeader(t *testing.T) {
+	a := assert.New(t)
+	data := "1,30,ignored,John Doe,john@example.com\n"
+	var sample IndexedStruct
+
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)), csv.WithNoHeader(true))
+	err := decoder.Decode(&sample)
+	a.NilNow(err)
+	a.EqualNow(IndexedStruct{Name: "John Doe", ID: 1, Email: "john@example.com", Age: 30}, sample)
+}
+
+func TestDecodeStructWithIndexedFieldsDetectedHeaderless(t *testing.T) {
+	a := assert.New(t)
+	data := "1,30,ignored,John Doe,john@example.com\n2,25,ignored,Jane Smith,jane@example.com\n"
+	var samples []IndexedStruct
+
+	err := csv.Unmarshal([]byte(data), &samples)
+	a.NilNow(err)
+	a.DeepEqualNow([]IndexedStruct{
+		{Name: "John Doe", ID: 1, Email: "john@example.com", Age: 30},
+		{Name: "Jane Smith", ID: 2, Email: "jane@example.com", Age: 25},
+	}, samples)
+}
+
+func TestDecoderStreaming(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n2,Jane Smith,25,3000,false\n"
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)))
+
+	expected := []SampleStruct{
+		{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true},
+		{ID: 2, Name: "Jane Smith", Age: 25, Salary: 3000, IsManager: false},
+	}
+
+	samples := make([]SampleStruct, 0, 2)
+	for decoder.More() {
+		var sample SampleStruct
+		err := decoder.Decode(&sample)
+		a.NilNow(err)
+		samples = append(samples, sample)
+	}
+	a.EqualNow(expected, samples)
+
+	var sample SampleStruct
+	err := decoder.Decode(&sample)
+	a.IsErrorNow(err, io.EOF)
+}
+
+func TestDecoderStreamingMalformedRecord(t *testing.T) {
+	a := assert.New(t)
+	data := "a,b\n1,x\n2,y,extra\n3,z\n"
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)))
+
+	type Row struct {
+		A int    `csv:"a"`
+		B string `csv:"b"`
+	}
+	var rows []Row
+	var errs []error
+	for decoder.More() {
+		var row Row
+		if err := decoder.Decode(&row); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	a.EqualNow([]Row{{A: 1, B: "x"}, {A: 3, B: "z"}}, rows)
+	a.EqualNow(1, len(errs))
+	a.IsErrorNow(errs[0], gocsv.ErrFieldCount)
+
+	// the malformed record right after the header
+	decoder = csv.NewDecoder(bytes.NewReader([]byte("a,b\n1,\"x\n")))
+	a.TrueNow(decoder.More())
+	var row Row
+	a.IsErrorNow(decoder.Decode(&row), gocsv.ErrQuote)
+	a.NotTrueNow(decoder.More())
+}
+
+func TestDecoderStreamingWithoutMore(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n2,Jane Smith,25,3000,false\n"
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)))
+
+	var sample SampleStruct
+	a.NilNow(decoder.Decode(&sample))
+	a.EqualNow(SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}, sample)
+
+	var rest []SampleStruct
+	a.NilNow(decoder.Decode(&rest))
+	a.EqualNow([]SampleStruct{{ID: 2, Name: "Jane Smith", Age: 25, Salary: 3000, IsManager: false}}, rest)
+
+	a.IsErrorNow(decoder.Decode(&sample), io.EOF)
+}
+
+func TestDecoderStreamingHeaderOnly(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n"
+	decoder := csv.NewDecoder(bytes.NewReader([]byte(data)))
+
+	a.TrueNow(decoder.More())
+	var sample SampleStruct
+	a.IsErrorNow(decoder.Decode(&sample), io.EOF)
+	a.NotTrueNow(decoder.More())
+}
+
+func TestAll(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n2,Jane Smith,25,3000,false\n"
+
+	expected := []SampleStruct{
+		{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true},
+		{ID: 2, Name: "Jane Smith", Age: 25, Salary: 3000, IsManager: false},
+	}
+
+	samples := make([]SampleStruct, 0, 2)
+	for sample, err := range csv.All[SampleStruct](bytes.NewReader([]byte(data))) {
+		a.NilNow(err)
+		samples = append(samples, sample)
+	}
+	a.EqualNow(expected, samples)
+}
+
+func TestAllWithPointer(t *testing.T) {
+	a := assert.New(t)
+	data := "1;John Doe;30;5500;true\n"
+
+	samples := make([]*SampleStruct, 0, 1)
+	for sample, err := range csv.All[*SampleStruct](bytes.NewReader([]byte(data)), csv.WithComma(';'), csv.WithNoHeader(true)) {
+		a.NilNow(err)
+		samples = append(samples, sample)
+	}
+	a.DeepEqualNow([]*SampleStruct{{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}}, samples)
+}
+
+func TestAllWithBreak(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n2,Jane Smith,25,3000,false\n"
+
+	n := 0
+	for sample, err := range csv.All[SampleStruct](bytes.NewReader([]byte(data))) {
+		a.NilNow(err)
+		a.EqualNow(1, sample.ID)
+		n++
+		break
+	}
+	a.EqualNow(1, n)
+}
+
+func TestAllWithInvalidRecord(t *testing.T) {
+	a := assert.New(t)
+	data := "id,name,age,salary,is_manager\n1,John Doe,thirty,5500,true\n2,Jane Smith,25,3000,false\n"
+
+	ids := make([]int, 0, 2)
+	errs := 0
+	for sample, err := range csv.All[SampleStruct](bytes.NewReader([]byte(data))) {
+		if err != nil {
+			errs++
+			a.EqualNow(SampleStruct{}, sample)
+			continue
+		}
+		ids = append(ids, sample.ID)
+	}
+	a.EqualNow(1, errs)
+	a.EqualNow([]int{2}, ids)
+}
+
 func ExampleUnmarshal() {
 	type Person struct {
 		ID   int    `csv:"id"`
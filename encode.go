@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"encoding"
 	"encoding/csv"
+	"fmt"
 	"io"
+	"math/big"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -17,9 +21,22 @@ type Marshaler interface {
 	MarshalCSV() ([]byte, error)
 }
 
+// An Encoder writes CSV records to an output stream.
+//
+// The header row is written only once, before the first record, so multiple
+// Encode calls with values of the same type append records to one document.
 type Encoder struct {
-	writer   *csv.Writer
-	noHeader bool
+	writer      *csv.Writer
+	noHeader    bool
+	nullString  string
+	keyOrder    func(a, b string) int
+	selected    []string
+	headerNames map[string]string
+	defaults    *fieldMeta
+
+	metaType reflect.Type
+	meta     []*fieldMeta
+	columns  []string
 }
 
 var encoderPool sync.Pool = sync.Pool{
@@ -45,6 +62,13 @@ func Marshal(v any) ([]byte, error) {
 func NewEncoder(writer io.Writer, opts ...CSVOption) *Encoder {
 	builder := newCSVBuilder(opts...)
 
+	if builder.charset != UTF8 {
+		writer = newCharsetWriter(writer, builder.charset)
+	}
+	if builder.bom && (builder.charset == UTF8 || builder.charset == UTF16LE || builder.charset == UTF16BE) {
+		writer = newBOMWriter(writer)
+	}
+
 	csvWriter := csv.NewWriter(writer)
 	csvWriter.Comma = builder.comma
 	csvWriter.UseCRLF = builder.useCRLF
@@ -54,15 +78,26 @@ func NewEncoder(writer io.Writer, opts ...CSVOption) *Encoder {
 		v = &Encoder{}
 	}
 	e := v.(*Encoder)
-	e.writer = csvWriter
-	e.noHeader = builder.noHeader
+	*e = Encoder{
+		writer:      csvWriter,
+		noHeader:    builder.noHeader,
+		nullString:  builder.nullString,
+		keyOrder:    builder.keyOrder,
+		selected:    builder.columns,
+		headerNames: builder.headerNames,
+		defaults:    builder.fieldDefaults,
+	}
 	return e
 }
 
 func (e *Encoder) Encode(v any) error {
-	defer e.writer.Flush()
+	err := e.marshal(v)
+	e.writer.Flush()
+	if err != nil {
+		return err
+	}
 
-	return e.marshal(v)
+	return e.writer.Error()
 }
 
 func (e *Encoder) marshal(v any) error {
@@ -71,20 +106,17 @@ func (e *Encoder) marshal(v any) error {
 		return nil
 	}
 
-	meta, err := reflectMetadata(rv)
+	ty, err := getValueType(rv)
 	if err != nil {
 		return err
 	}
+	if ty.Kind() == reflect.Map {
+		return e.marshalMaps(rv, ty)
+	}
 
-	// write header
-	if !e.noHeader {
-		header := make([]string, len(meta))
-		for i, m := range meta {
-			header[i] = m.Name
-		}
-		if err := e.writer.Write(header); err != nil {
-			return err
-		}
+	meta, err := e.getMetaFields(rv)
+	if err != nil {
+		return err
 	}
 
 	switch rv.Kind() {
@@ -115,18 +147,261 @@ func (e *Encoder) marshal(v any) error {
 	return nil
 }
 
+// getMetaFields returns the fields metadata of the value, and writes the
+// header row if it has not been written yet. It returns ErrIncompatibleType
+// if the columns of the value differ from the columns of the header written
+// by a previous call.
+func (e *Encoder) getMetaFields(rv reflect.Value) ([]*fieldMeta, error) {
+	ty, _ := getValueType(rv)
+	if e.metaType != nil && ty == e.metaType {
+		return e.meta, nil
+	}
+
+	meta, err := reflectMetadata(rv)
+	if err != nil {
+		return nil, err
+	}
+	meta = mergeMetaFields(meta, e.defaults)
+	if e.selected != nil {
+		meta, err = selectMetaFields(meta, e.selected)
+	} else {
+		meta, err = positionalMetaFields(meta)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, len(meta))
+	for i, m := range meta {
+		if m != nil {
+			columns[i] = m.Name
+		}
+	}
+
+	if e.metaType != nil {
+		if !slices.Equal(columns, e.columns) {
+			return nil, ErrIncompatibleType
+		}
+		return meta, nil
+	}
+
+	if err := e.writeHeader(ty, columns); err != nil {
+		return nil, err
+	}
+	e.meta = meta
+	return meta, nil
+}
+
+// selectMetaFields returns the fields metadata of the columns in the order of
+// the columns. It returns ErrUnknownColumn if any column has no matching field.
+func selectMetaFields(meta []*fieldMeta, columns []string) ([]*fieldMeta, error) {
+	selected := make([]*fieldMeta, 0, len(columns))
+	for _, col := range columns {
+		idx := slices.IndexFunc(meta, func(m *fieldMeta) bool {
+			return m.Name == col
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, col)
+		}
+		selected = append(selected, meta[idx])
+	}
+
+	return selected, nil
+}
+
+// writeHeader writes the header row if the header is enabled, and records the
+// columns of the document.
+func (e *Encoder) writeHeader(ty reflect.Type, columns []string) error {
+	if !e.noHeader {
+		header := columns
+		if e.headerNames != nil {
+			header = make([]string, len(columns))
+			for i, col := range columns {
+				header[i] = col
+				if name, ok := e.headerNames[col]; ok {
+					header[i] = name
+				}
+			}
+		}
+
+		if err := e.writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	e.metaType, e.columns = ty, columns
+	return nil
+}
+
+// marshalMaps writes the maps of the value as records. The columns are the
+// union of the keys of all maps, sorted by the key order of the encoder. For
+// a channel, the columns are the keys of the first received map. If the
+// columns are set by WithColumns, the keys of the maps out of the columns are
+// ignored.
+//
+// If the header has been written by a previous call, the maps are written
+// with its columns, and ErrIncompatibleType is returned if any map has a key
+// that is not a column of the header.
+func (e *Encoder) marshalMaps(rv reflect.Value, ty reflect.Type) error {
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	var first reflect.Value
+	if rv.Kind() == reflect.Chan {
+		elem, ok := rv.Recv()
+		if ok {
+			first = elem
+		}
+	}
+
+	if e.metaType == nil && e.selected != nil {
+		if err := e.writeHeader(ty, e.selected); err != nil {
+			return err
+		}
+	} else if e.metaType == nil {
+		keys := make(map[string]struct{})
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				addMapKeys(keys, rv.Index(i))
+			}
+		case reflect.Chan:
+			if first.IsValid() {
+				addMapKeys(keys, first)
+			}
+		default:
+			addMapKeys(keys, rv)
+		}
+
+		columns := make([]string, 0, len(keys))
+		for key := range keys {
+			columns = append(columns, key)
+		}
+		slices.SortFunc(columns, e.keyOrder)
+
+		if err := e.writeHeader(ty, columns); err != nil {
+			return err
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := e.writeMapRow(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Chan:
+		if !first.IsValid() {
+			return nil
+		}
+		for elem, ok := first, true; ok; elem, ok = rv.Recv() {
+			if err := e.writeMapRow(elem); err != nil {
+				return err
+			}
+		}
+	default:
+		return e.writeMapRow(rv)
+	}
+
+	return nil
+}
+
+// addMapKeys adds the keys of the map into the keys set.
+func addMapKeys(keys map[string]struct{}, v reflect.Value) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	iter := v.MapRange()
+	for iter.Next() {
+		keys[iter.Key().String()] = struct{}{}
+	}
+}
+
+func (e *Encoder) writeMapRow(v reflect.Value) error {
+	row := make([]string, len(e.columns))
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			for i := range row {
+				row[i] = e.nullString
+			}
+			return e.writer.Write(row)
+		}
+		v = v.Elem()
+	}
+
+	if e.selected == nil {
+		for _, key := range v.MapKeys() {
+			if !slices.Contains(e.columns, key.String()) {
+				return ErrIncompatibleType
+			}
+		}
+	}
+
+	for i, col := range e.columns {
+		fv := v.MapIndex(reflect.ValueOf(col).Convert(v.Type().Key()))
+		if !fv.IsValid() {
+			continue
+		}
+		if fv.Kind() == reflect.Interface {
+			fv = fv.Elem()
+		}
+		if !fv.IsValid() || (fv.Kind() == reflect.Ptr && fv.IsNil()) {
+			row[i] = e.nullString
+			continue
+		}
+
+		m := newFieldMeta(col)
+		m.Type = fv.Type()
+		if e.defaults != nil {
+			m = m.withDefaults(e.defaults)
+		}
+
+		str, err := typeEncoder(fv.Type())(fv, m)
+		if err != nil {
+			return err
+		}
+		row[i] = str
+	}
+
+	return e.writer.Write(row)
+}
+
 func (e *Encoder) writeRow(v reflect.Value, meta []*fieldMeta) error {
 	row := make([]string, len(meta))
 
 	for v.Kind() == reflect.Ptr {
 		if v.IsNil() {
+			for i, m := range meta {
+				if m != nil {
+					row[i] = e.nullString
+				}
+			}
 			return e.writer.Write(row)
 		}
 		v = v.Elem()
 	}
 
 	for i, m := range meta {
-		fv := v.Field(m.Index)
+		if m == nil {
+			continue
+		}
+
+		fv, ok := fieldByIndexNoAlloc(v, m.Index)
+		if m.OmitEmpty && (!ok || fv.IsZero()) {
+			continue
+		}
+		if !ok || (fv.Kind() == reflect.Ptr && fv.IsNil()) {
+			row[i] = e.nullString
+			continue
+		}
+
 		str, err := valueEncoder(m)(fv, m)
 		if err != nil {
 			return err
@@ -176,6 +451,10 @@ var (
 	marshalerType     = reflect.TypeFor[Marshaler]()
 	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
 	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	bigIntType        = reflect.TypeFor[big.Int]()
+	bigFloatType      = reflect.TypeFor[big.Float]()
+	bigRatType        = reflect.TypeFor[big.Rat]()
 )
 
 func newTypeEncoder(t reflect.Type) encoderFunc {
@@ -187,18 +466,30 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 	case reflect.Bool:
 		return boolEncoder
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == durationType {
+			return durationEncoder
+		}
 		return intEncoder
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return uintEncoder
 	case reflect.Float32, reflect.Float64:
 		return floatEncoder
+	case reflect.Complex64, reflect.Complex128:
+		return complexEncoder
 	case reflect.String:
 		return stringEncoder
 	case reflect.Ptr:
 		return newPtrEncoder(t)
 	case reflect.Struct:
-		if t.ConvertibleTo(timeType) {
+		switch {
+		case t.ConvertibleTo(timeType):
 			return timeEncoder
+		case t == bigIntType:
+			return bigIntEncoder
+		case t == bigFloatType:
+			return bigFloatEncoder
+		case t == bigRatType:
+			return bigRatEncoder
 		}
 	}
 
@@ -209,39 +500,157 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 	return unsupportedTypeEncoder
 }
 
-func boolEncoder(v reflect.Value, _ *fieldMeta) (string, error) {
+func boolEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	// the unset token falls back to the default tokens
+	if v.Bool() && m.TrueToken != "" {
+		return m.TrueToken, nil
+	}
+	if !v.Bool() && m.FalseToken != "" {
+		return m.FalseToken, nil
+	}
+
 	s := strconv.FormatBool(v.Bool())
 	return s, nil
 }
 
-func intEncoder(v reflect.Value, _ *fieldMeta) (string, error) {
+func intEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	if strings.HasPrefix(m.Format, "%") {
+		return m.Number.localize(fmt.Sprintf(m.Format, v.Int())), nil
+	}
+	if m.Base != 10 {
+		return strconv.FormatInt(v.Int(), formatBase(m.Base)), nil
+	}
+
 	s := strconv.FormatInt(v.Int(), 10)
-	return s, nil
+	return m.Number.localize(s), nil
 }
 
-func uintEncoder(v reflect.Value, _ *fieldMeta) (string, error) {
+func uintEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	if strings.HasPrefix(m.Format, "%") {
+		return m.Number.localize(fmt.Sprintf(m.Format, v.Uint())), nil
+	}
+	if m.Base != 10 {
+		return strconv.FormatUint(v.Uint(), formatBase(m.Base)), nil
+	}
+
 	s := strconv.FormatUint(v.Uint(), 10)
-	return s, nil
+	return m.Number.localize(s), nil
 }
 
-func floatEncoder(v reflect.Value, _ *fieldMeta) (string, error) {
-	s := strconv.FormatFloat(v.Float(), 'f', -1, 64)
+// formatBase returns the base to format the integers, the base 0 that detects
+// the base by the prefix on decoding is formatted in base 10.
+func formatBase(base int) int {
+	if base == 0 {
+		return 10
+	}
+	return base
+}
+
+func floatEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	if strings.HasPrefix(m.Format, "%") {
+		return m.Number.localize(fmt.Sprintf(m.Format, v.Float())), nil
+	}
+
+	format := m.FloatFmt
+	if format == 0 {
+		format = 'f'
+	}
+	s := strconv.FormatFloat(v.Float(), format, m.Precision, v.Type().Bits())
+	if !isDecimalFormat(format) {
+		return s, nil
+	}
+	return m.Number.localize(s), nil
+}
+
+func complexEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	format := m.FloatFmt
+	if format == 0 {
+		format = 'f'
+	}
+	s := strconv.FormatComplex(v.Complex(), format, m.Precision, v.Type().Bits())
 	return s, nil
 }
 
+func bigIntEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	x := addrOf(v).(*big.Int)
+	if strings.HasPrefix(m.Format, "%") {
+		return m.Number.localize(fmt.Sprintf(m.Format, x)), nil
+	}
+	if m.Base != 10 {
+		return x.Text(formatBase(m.Base)), nil
+	}
+
+	return m.Number.localize(x.String()), nil
+}
+
+func bigFloatEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	x := addrOf(v).(*big.Float)
+	if strings.HasPrefix(m.Format, "%") {
+		return m.Number.localize(fmt.Sprintf(m.Format, x)), nil
+	}
+
+	format := m.FloatFmt
+	if format == 0 {
+		format = 'f'
+	}
+	s := x.Text(format, m.Precision)
+	if !isDecimalFormat(format) {
+		return s, nil
+	}
+	return m.Number.localize(s), nil
+}
+
+func bigRatEncoder(v reflect.Value, m *fieldMeta) (string, error) {
+	x := addrOf(v).(*big.Rat)
+	if m.Precision < 0 {
+		// the exact fraction, or the integer if the denominator is 1
+		return m.Number.localize(x.RatString()), nil
+	}
+
+	return m.Number.localize(x.FloatString(m.Precision)), nil
+}
+
+// addrOf returns the pointer to the value, or to a copy of the value if it's
+// not addressable.
+func addrOf(v reflect.Value) any {
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Interface()
+}
+
 func stringEncoder(v reflect.Value, _ *fieldMeta) (string, error) {
 	return v.String(), nil
 }
 
+func durationEncoder(v reflect.Value, _ *fieldMeta) (string, error) {
+	return time.Duration(v.Int()).String(), nil
+}
+
 func timeEncoder(v reflect.Value, m *fieldMeta) (string, error) {
-	tm := v.Interface().(time.Time)
+	tm := v.Convert(timeType).Interface().(time.Time)
+	if m.Location != nil {
+		tm = tm.In(m.Location)
+	}
 
 	if m.Format != "" {
-		return tm.Format(m.Format), nil
+		// the first layout of the fallback layouts is used for encoding
+		layout, _, _ := strings.Cut(m.Format, "|")
+		switch layout {
+		case "unix":
+			return strconv.FormatInt(tm.Unix(), 10), nil
+		case "unixms":
+			return strconv.FormatInt(tm.UnixMilli(), 10), nil
+		default:
+			return tm.Format(layout), nil
+		}
 	}
 
 	// fallback to TextMarshalerEncoder
-	return textMarshalerEncoder(v, m)
+	return textMarshalerEncoder(reflect.ValueOf(tm), m)
 }
 
 func marshalerEncoder(v reflect.Value, _ *fieldMeta) (string, error) {
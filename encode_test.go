@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
@@ -225,6 +227,169 @@ func TestEncodeStructWithNilPointerFields(t *testing.T) {
 	a.EqualNow(expected, string(data))
 }
 
+type OmitEmptyStruct struct {
+	ID        int        `csv:"id"`
+	Age       int        `csv:"age,omitempty"`
+	IsManager bool       `csv:"is_manager,omitempty"`
+	JoinedAt  time.Time  `csv:"joined_at,omitempty"`
+	Salary    *float64   `csv:"salary"`
+	LeftAt    *time.Time `csv:"left_at,omitempty"`
+}
+
+func TestEncodeStructWithOmitEmptyFields(t *testing.T) {
+	a := assert.New(t)
+	sample := OmitEmptyStruct{ID: 1}
+
+	data, err := csv.Marshal(sample)
+	a.NilNow(err)
+	expected := "id,age,is_manager,joined_at,salary,left_at\n1,,,,,\n"
+	a.EqualNow(expected, string(data))
+}
+
+func TestEncoderWithNullStringOption(t *testing.T) {
+	a := assert.New(t)
+	id, name := 1, "John Doe"
+	samples := []*SimplePointerStruct{
+		{ID: &id, Name: &name},
+		nil,
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithNullString("NULL"))
+	err := encoder.Encode(samples)
+	a.NilNow(err)
+	expected := "id,name,age,salary,is_manager\n1,John Doe,NULL,NULL,NULL\nNULL,NULL,NULL,NULL,NULL\n"
+	a.EqualNow(expected, buf.String())
+}
+
+type Audit struct {
+	CreatedAt time.Time `csv:"created_at,format=2006-01-02"`
+	UpdatedBy string    `csv:"updated_by"`
+}
+
+type Tags struct {
+	Name string `csv:"tag_name"`
+	Note string `csv:"note"`
+}
+
+type EmbeddedStruct struct {
+	ID   int    `csv:"id"`
+	Note string `csv:"note"`
+	Audit
+	*Tags
+}
+
+func TestEncodeStructWithEmbeddedStruct(t *testing.T) {
+	a := assert.New(t)
+	sample := EmbeddedStruct{
+		ID:    1,
+		Note:  "first",
+		Audit: Audit{CreatedAt: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), UpdatedBy: "admin"},
+		Tags:  &Tags{Name: "tag", Note: "shadowed"},
+	}
+
+	data, err := csv.Marshal(sample)
+	a.NilNow(err)
+	expected := "id,note,created_at,updated_by,tag_name\n1,first,2025-10-01,admin,tag\n"
+	a.EqualNow(expected, string(data))
+}
+
+func TestEncodeStructWithNilEmbeddedPointer(t *testing.T) {
+	a := assert.New(t)
+	sample := EmbeddedStruct{ID: 1, Note: "first"}
+
+	data, err := csv.Marshal(sample)
+	a.NilNow(err)
+	expected := "id,note,created_at,updated_by,tag_name\n1,first,0001-01-01,,\n"
+	a.EqualNow(expected, string(data))
+}
+
+type ConflictA struct {
+	Name string
+}
+
+type ConflictB struct {
+	Name string
+}
+
+type ConflictEmbeddedStruct struct {
+	ID int
+	ConflictA
+	ConflictB
+}
+
+func TestEncodeStructWithConflictEmbeddedFields(t *testing.T) {
+	a := assert.New(t)
+	sample := ConflictEmbeddedStruct{ID: 1, ConflictA: ConflictA{"a"}, ConflictB: ConflictB{"b"}}
+
+	data, err := csv.Marshal(sample)
+	a.NilNow(err)
+	a.EqualNow("ID\n1\n", string(data))
+}
+
+type Geo struct {
+	Lat float64 `csv:"lat"`
+	Lng float64 `csv:"lng"`
+}
+
+type Address struct {
+	Street string `csv:"street"`
+	City   string `csv:"city"`
+	Geo    *Geo   `csv:"geo,inline"`
+}
+
+type InlineStruct struct {
+	ID       int      `csv:"id"`
+	Billing  Address  `csv:"billing,inline"`
+	Shipping *Address `csv:"shipping,inline"`
+}
+
+func TestEncodeStructWithInlineFields(t *testing.T) {
+	a := assert.New(t)
+	sample := InlineStruct{
+		ID:      1,
+		Billing: Address{Street: "1 Main St", City: "Springfield", Geo: &Geo{Lat: 1.5, Lng: 2.5}},
+	}
+
+	data, err := csv.Marshal(sample)
+	a.NilNow(err)
+	expected := "id,billing.street,billing.city,billing.geo.lat,billing.geo.lng," +
+		"shipping.street,shipping.city,shipping.geo.lat,shipping.geo.lng\n" +
+		"1,1 Main St,Springfield,1.5,2.5,,,,\n"
+	a.EqualNow(expected, string(data))
+}
+
+type IndexedStruct struct {
+	Name  string `csv:"name,index=3"`
+	ID    int    `csv:"id,index=0"`
+	Email string `csv:"email"`
+	Age   int    `csv:"age,index=1"`
+}
+
+func TestEncodeStructWithIndexedFields(t *testing.T) {
+	a := assert.New(t)
+	samples := []*IndexedStruct{
+		{Name: "John Doe", ID: 1, Email: "john@example.com", Age: 30},
+		nil,
+	}
+
+	data, err := csv.Marshal(samples)
+	a.NilNow(err)
+	expected := "id,age,,name,email\n1,30,,John Doe,john@example.com\n,,,,\n"
+	a.EqualNow(expected, string(data))
+}
+
+type DuplicateIndexStruct struct {
+	ID   int    `csv:"id,index=0"`
+	Name string `csv:"name,index=0"`
+}
+
+func TestEncodeStructWithDuplicateIndex(t *testing.T) {
+	a := assert.New(t)
+	_, err := csv.Marshal(DuplicateIndexStruct{})
+	a.IsErrorNow(err, csv.ErrDuplicateColumn)
+}
+
 type MarshalableStruct struct {
 	Country string
 	ZipCode int
@@ -456,6 +621,124 @@ func TestEncodeTimeStruct(t *testing.T) {
 	a.EqualNow(string(data), expected)
 }
 
+type ZonedTimeStruct struct {
+	Timeout  time.Duration `csv:"timeout"`
+	Local    time.Time     `csv:"local,format=2006-01-02 15:04|2006-01-02,tz=Europe/Berlin"`
+	Unix     time.Time     `csv:"unix,format=unix"`
+	UnixMs   time.Time     `csv:"unix_ms,format=unixms"`
+	Deadline time.Time     `csv:"deadline,tz=Europe/Berlin"`
+}
+
+func TestEncodeZonedTimeStruct(t *testing.T) {
+	a := assert.New(t)
+	tm := time.Date(2025, 10, 1, 11, 30, 00, 0, time.UTC)
+	sample := ZonedTimeStruct{
+		Timeout:  90 * time.Second,
+		Local:    tm,
+		Unix:     tm,
+		UnixMs:   tm.Add(250 * time.Millisecond),
+		Deadline: tm,
+	}
+
+	data, err := csv.Marshal(sample)
+	a.NilNow(err)
+	expected := "timeout,local,unix,unix_ms,deadline\n1m30s,2025-10-01 13:30,1759318200,1759318200250,2025-10-01T13:30:00+02:00\n"
+	a.EqualNow(expected, string(data))
+}
+
+type BigNumberStruct struct {
+	Count   big.Int     `csv:"count,group=comma"`
+	Amount  *big.Float  `csv:"amount,prec=2,bits=128"`
+	Ratio   big.Rat     `csv:"ratio"`
+	Share   *big.Rat    `csv:"share,prec=4"`
+	Signal  complex64   `csv:"signal"`
+	Impulse *complex128 `csv:"impulse,fmt=e,prec=2"`
+}
+
+func TestEncodeBigNumberStruct(t *testing.T) {
+	a := assert.New(t)
+	impulse := complex(1500, -0.5)
+	sample := BigNumberStruct{
+		Amount:  big.NewFloat(1234.565).SetPrec(128),
+		Ratio:   *big.NewRat(1, 3),
+		Share:   big.NewRat(2, 3),
+		Signal:  complex(0.1, 2),
+		Impulse: &impulse,
+	}
+	sample.Count.SetString("123456789012345678901234567890", 10)
+
+	data, err := csv.Marshal(&sample)
+	a.NilNow(err)
+	expected := "count,amount,ratio,share,signal,impulse\n" +
+		"\"123,456,789,012,345,678,901,234,567,890\",1234.57,1/3,0.6667,(0.1+2i),(1.50e+03-5.00e-01i)\n"
+	a.EqualNow(expected, string(data))
+}
+
+func TestEncodeAnyMapSlice(t *testing.T) {
+	a := assert.New(t)
+	rows := []map[string]any{
+		{"id": 1, "name": "John Doe", "salary": 5500.5},
+		{"id": 2, "name": "Jane Smith", "is_manager": true, "email": nil},
+	}
+
+	data, err := csv.Marshal(rows)
+	a.NilNow(err)
+	expected := "email,id,is_manager,name,salary\n,1,,John Doe,5500.5\n,2,true,Jane Smith,\n"
+	a.EqualNow(expected, string(data))
+}
+
+func TestEncodeStringMap(t *testing.T) {
+	a := assert.New(t)
+	row := map[string]string{"name": "John Doe", "id": "1"}
+
+	data, err := csv.Marshal(row)
+	a.NilNow(err)
+	a.EqualNow("id,name\n1,John Doe\n", string(data))
+}
+
+func TestEncodeStringMapChannel(t *testing.T) {
+	a := assert.New(t)
+	rows := make(chan map[string]string, 2)
+	rows <- map[string]string{"id": "1", "name": "John Doe"}
+	rows <- map[string]string{"id": "2"}
+	close(rows)
+
+	data, err := csv.Marshal(rows)
+	a.NilNow(err)
+	a.EqualNow("id,name\n1,John Doe\n2,\n", string(data))
+}
+
+func TestEncoderWithKeyOrderOption(t *testing.T) {
+	a := assert.New(t)
+	order := []string{"id", "name", "email"}
+	rows := []map[string]string{
+		{"email": "john@example.com", "name": "John Doe", "id": "1"},
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithKeyOrder(func(a, b string) int {
+		return slices.Index(order, a) - slices.Index(order, b)
+	}))
+	err := encoder.Encode(rows)
+	a.NilNow(err)
+	a.EqualNow("id,name,email\n1,John Doe,john@example.com\n", buf.String())
+}
+
+func TestEncoderMultipleEncodeMaps(t *testing.T) {
+	a := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithNullString("NULL"))
+	err := encoder.Encode(SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true})
+	a.NilNow(err)
+	err = encoder.Encode(map[string]any{"id": 2, "name": nil})
+	a.NilNow(err)
+	err = encoder.Encode(map[string]any{"id": 3, "email": "bob@example.com"})
+	a.IsErrorNow(err, csv.ErrIncompatibleType)
+	expected := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n2,NULL,,,\n"
+	a.EqualNow(expected, buf.String())
+}
+
 func TestEncoder_Encode(t *testing.T) {
 	a := assert.New(t)
 	sample := SampleStruct{
@@ -510,6 +793,137 @@ func TestEncoderWithCRLFOption(t *testing.T) {
 	a.EqualNow(expected, buf.String())
 }
 
+func TestEncoderWithBOMOption(t *testing.T) {
+	a := assert.New(t)
+	sample := SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithBOM(true))
+	a.EqualNow(0, buf.Len())
+	err := encoder.Encode(sample)
+	a.NilNow(err)
+	err = encoder.Encode(sample)
+	a.NilNow(err)
+	expected := "\xEF\xBB\xBFid,name,age,salary,is_manager\n1,John Doe,30,5500,true\n1,John Doe,30,5500,true\n"
+	a.EqualNow(expected, buf.String())
+}
+
+func TestEncoderWithBoolTokensOption(t *testing.T) {
+	a := assert.New(t)
+	yes := true
+	samples := []BoolTokenStruct{
+		{Active: true, Verified: &yes},
+		{Active: false},
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithBoolTokens("yes", "no"))
+	err := encoder.Encode(samples)
+	a.NilNow(err)
+	err = encoder.Encode(map[string]any{"active": false, "verified": true})
+	a.NilNow(err)
+	a.EqualNow("active,verified\nY,yes\nN,\nno,yes\n", buf.String())
+}
+
+func TestEncoderWithNumberFormatOption(t *testing.T) {
+	a := assert.New(t)
+	samples := []LocaleNumberStruct{
+		{Price: 1234567.5, Quantity: -2500, Rate: 12.5},
+		{Price: -0.25, Quantity: 100, Rate: 1000},
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithNumberFormat(csv.NumberFormat{Group: ','}))
+	err := encoder.Encode(samples)
+	a.NilNow(err)
+	a.EqualNow("price,quantity,rate\n\"€1.234.567,5\",\"-2,500\",12.5%\n\"-€0,25\",100,1000%\n", buf.String())
+
+	var decoded []LocaleNumberStruct
+	decoder := csv.NewDecoder(bytes.NewReader(buf.Bytes()), csv.WithNumberFormat(csv.NumberFormat{Group: ','}))
+	err = decoder.Decode(&decoded)
+	a.NilNow(err)
+	a.DeepEqualNow(samples, decoded)
+}
+
+type FloatFormatStruct struct {
+	Ratio   float32 `csv:"ratio"`
+	Amount  float64 `csv:"amount,prec=2"`
+	Mass    float64 `csv:"mass,fmt=e,prec=3"`
+	Energy  float64 `csv:"energy,fmt=e"`
+	Percent float64 `csv:"percent,format=%.1f%%"`
+}
+
+func TestEncodeStructWithFloatFormat(t *testing.T) {
+	a := assert.New(t)
+	sample := FloatFormatStruct{Ratio: 0.1, Amount: 12.345, Mass: 5972000, Energy: 1234.5, Percent: 99.95}
+
+	data, err := csv.Marshal([]FloatFormatStruct{sample})
+	a.NilNow(err)
+	a.EqualNow("ratio,amount,mass,energy,percent\n0.1,12.35,5.972e+06,1.2345e+03,100.0%\n", string(data))
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithFloatFormat('f', 2))
+	err = encoder.Encode(sample)
+	a.NilNow(err)
+	a.EqualNow("ratio,amount,mass,energy,percent\n0.10,12.35,5.972e+06,1.23e+03,100.0%\n", buf.String())
+
+	buf.Reset()
+	encoder = csv.NewEncoder(buf, csv.WithFloatFormat('g', 2), csv.WithColumns("ratio", "amount"))
+	err = encoder.Encode(FloatFormatStruct{Ratio: 1234.5, Amount: 12.345})
+	a.NilNow(err)
+	a.EqualNow("ratio,amount\n1.2e+03,12\n", buf.String())
+}
+
+type HexFloatStruct struct {
+	Value float64 `csv:"value,fmt=x"`
+}
+
+func TestEncodeStructWithHexFloatFormat(t *testing.T) {
+	a := assert.New(t)
+	samples := []HexFloatStruct{{Value: 1234.5}, {Value: -0.375}}
+	format := csv.WithNumberFormat(csv.NumberFormat{Decimal: ',', Group: '.'})
+
+	buf := &bytes.Buffer{}
+	err := csv.NewEncoder(buf, format).Encode(samples)
+	a.NilNow(err)
+	a.EqualNow("value\n0x1.34ap+10\n-0x1.8p-02\n", buf.String())
+
+	var decoded []HexFloatStruct
+	err = csv.NewDecoder(bytes.NewReader(buf.Bytes()), format).Decode(&decoded)
+	a.NilNow(err)
+	a.DeepEqualNow(samples, decoded)
+
+	_, err = csv.Marshal([]struct {
+		Value float64 `csv:"value,fmt=b"`
+	}{{Value: 1}})
+	a.IsErrorNow(err, csv.ErrInvalidTag)
+}
+
+type HexStruct struct {
+	ID    uint32 `csv:"id,base=16"`
+	Flags int16  `csv:"flags,format=%#x"`
+	Mode  int    `csv:"mode,base=8"`
+}
+
+func TestEncodeStructWithIntegerBase(t *testing.T) {
+	a := assert.New(t)
+	data, err := csv.Marshal([]HexStruct{{ID: 0xdeadbeef, Flags: 16, Mode: 0755}, {ID: 1, Flags: -1, Mode: -8}})
+	a.NilNow(err)
+	a.EqualNow("id,flags,mode\ndeadbeef,0x10,755\n1,-0x1,-10\n", string(data))
+}
+
+type HalfBoolTokenStruct struct {
+	ID     int  `csv:"id"`
+	Active bool `csv:"active,true=Y"`
+}
+
+func TestEncodeStructWithHalfBoolTokens(t *testing.T) {
+	a := assert.New(t)
+	data, err := csv.Marshal([]HalfBoolTokenStruct{{ID: 1, Active: true}, {ID: 2, Active: false}})
+	a.NilNow(err)
+	a.EqualNow("id,active\n1,Y\n2,false\n", string(data))
+}
+
 func TestEncoderWithNoHeaderOption(t *testing.T) {
 	a := assert.New(t)
 	sample := SampleStruct{
@@ -528,6 +942,83 @@ func TestEncoderWithNoHeaderOption(t *testing.T) {
 	a.EqualNow(expected, buf.String())
 }
 
+func TestEncoderMultipleEncode(t *testing.T) {
+	a := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf)
+	err := encoder.Encode(SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true})
+	a.NilNow(err)
+	err = encoder.Encode([]*SampleStruct{{ID: 2, Name: "Jane Smith", Age: 25, Salary: 3000, IsManager: false}})
+	a.NilNow(err)
+	expected := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n2,Jane Smith,25,3000,false\n"
+	a.EqualNow(expected, buf.String())
+}
+
+func TestEncoderMultipleEncodeWithIncompatibleType(t *testing.T) {
+	a := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf)
+	err := encoder.Encode(SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true})
+	a.NilNow(err)
+	err = encoder.Encode(NoTagStruct{ID: 2, Name: "Jane Smith"})
+	a.IsErrorNow(err, csv.ErrIncompatibleType)
+	expected := "id,name,age,salary,is_manager\n1,John Doe,30,5500,true\n"
+	a.EqualNow(expected, buf.String())
+}
+
+func TestEncoderWithColumnsOption(t *testing.T) {
+	a := assert.New(t)
+	sample := SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithColumns("name", "id"))
+	err := encoder.Encode(sample)
+	a.NilNow(err)
+	a.EqualNow("name,id\nJohn Doe,1\n", buf.String())
+}
+
+func TestEncoderWithColumnsOptionUnknownColumn(t *testing.T) {
+	a := assert.New(t)
+	sample := SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithColumns("id", "email"))
+	err := encoder.Encode(sample)
+	a.IsErrorNow(err, csv.ErrUnknownColumn)
+}
+
+func TestEncoderWithColumnsOptionMaps(t *testing.T) {
+	a := assert.New(t)
+	rows := []map[string]any{
+		{"id": 1, "name": "John Doe", "salary": 5500},
+		{"id": 2, "email": "jane@example.com"},
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithColumns("name", "id", "email"))
+	err := encoder.Encode(rows)
+	a.NilNow(err)
+	a.EqualNow("name,id,email\nJohn Doe,1,\n,2,jane@example.com\n", buf.String())
+}
+
+func TestEncoderWithHeaderNamesOption(t *testing.T) {
+	a := assert.New(t)
+	sample := SampleStruct{ID: 1, Name: "John Doe", Age: 30, Salary: 5500, IsManager: true}
+
+	buf := &bytes.Buffer{}
+	encoder := csv.NewEncoder(buf, csv.WithColumns("id", "name"), csv.WithHeaderNames(map[string]string{
+		"id":   "Employee ID",
+		"name": "Full Name",
+	}))
+	err := encoder.Encode(sample)
+	a.NilNow(err)
+	err = encoder.Encode(sample)
+	a.NilNow(err)
+	a.EqualNow("Employee ID,Full Name\n1,John Doe\n1,John Doe\n", buf.String())
+}
+
 func ExampleMarshal() {
 	type Person struct {
 		ID   int
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 )
 
 var (
@@ -11,6 +13,12 @@ var (
 	ErrUnsupportedType  = errors.New("csv: unsupported type")
 	ErrCannotSet        = errors.New("csv: cannot set value to nil pointer")
 	ErrInvalidUnmarshal = errors.New("csv: Unmarshal(nil)")
+	ErrIncompatibleType = errors.New("csv: incompatible type with the written header")
+	ErrRequiredField    = errors.New("csv: required field is empty")
+	ErrUnknownColumn    = errors.New("csv: unknown column")
+	ErrDuplicateColumn  = errors.New("csv: duplicate column")
+	ErrInvalidCharacter = errors.New("csv: character not representable in the charset")
+	ErrInvalidTag       = errors.New("csv: invalid tag option")
 )
 
 func newInvalidUnmarshalError(rv reflect.Value) error {
@@ -21,9 +29,20 @@ func newInvalidUnmarshalError(rv reflect.Value) error {
 	return errors.New("csv: Unmarshal(nil " + rv.Type().Name() + ")")
 }
 
+// newInvalidTagError returns the error of the invalid tag option, wrapping
+// the cause if it's not nil.
+func newInvalidTagError(option string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidTag, option, err)
+	}
+	return fmt.Errorf("%w %q", ErrInvalidTag, option)
+}
+
+// DecodeError describes an error of decoding a cell into a struct field.
 type DecodeError struct {
 	line   int
 	column int
+	offset int64
 	field  string
 	value  string
 	err    error
@@ -38,28 +57,102 @@ func (e *DecodeError) Unwrap() error {
 	return e.err
 }
 
+// Row returns the line number (1-based) of the cell in the input.
 func (e *DecodeError) Row() int {
 	return e.line
 }
 
+// Col returns the column (1-based byte index in the line) of the cell in the
+// input, or 0 if the record has no such cell.
 func (e *DecodeError) Col() int {
 	return e.column
 }
 
+// Offset returns the byte offset of the input where the decoder started to
+// read the record of the cell.
+func (e *DecodeError) Offset() int64 {
+	return e.offset
+}
+
+// Field returns the column name of the field.
 func (e *DecodeError) Field() string {
 	return e.field
 }
 
+// Value returns the value of the cell.
 func (e *DecodeError) Value() string {
 	return e.value
 }
 
-func newDecodeError(line, column int, field, value string, err error) *DecodeError {
+func newDecodeError(line, column int, offset int64, field, value string, err error) *DecodeError {
 	return &DecodeError{
 		line:   line,
 		column: column,
+		offset: offset,
 		field:  field,
 		value:  value,
 		err:    err,
 	}
 }
+
+// DecodeErrors holds the errors of all invalid records skipped by the decoder
+// in the error collecting mode.
+type DecodeErrors struct {
+	errs []*DecodeError
+}
+
+func (e *DecodeErrors) Error() string {
+	if len(e.errs) == 1 {
+		return e.errs[0].Error()
+	}
+
+	return fmt.Sprintf("%s (and %d more errors)", e.errs[0].Error(), len(e.errs)-1)
+}
+
+func (e *DecodeErrors) Unwrap() []error {
+	errs := make([]error, len(e.errs))
+	for i, err := range e.errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Errors returns the errors of the skipped records, in the order they occurred.
+func (e *DecodeErrors) Errors() []*DecodeError {
+	return e.errs
+}
+
+func newDecodeErrors(errs []*DecodeError) *DecodeErrors {
+	return &DecodeErrors{
+		errs: slices.Clone(errs),
+	}
+}
+
+// HeaderError is returned by the decoder in the strict header mode if the
+// header row does not match the fields of the struct.
+type HeaderError struct {
+	unknown []string
+	missing []string
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("csv: header mismatch (unknown columns: [%s], missing fields: [%s])",
+		strings.Join(e.unknown, ", "), strings.Join(e.missing, ", "))
+}
+
+// Unknown returns the columns of the header row without a matching field.
+func (e *HeaderError) Unknown() []string {
+	return e.unknown
+}
+
+// Missing returns the names of the fields without a matching column.
+func (e *HeaderError) Missing() []string {
+	return e.missing
+}
+
+func newHeaderError(unknown, missing []string) *HeaderError {
+	return &HeaderError{
+		unknown: unknown,
+		missing: missing,
+	}
+}
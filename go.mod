@@ -1,5 +1,5 @@
 module github.com/ghosind/go-csv
 
-go 1.22
+go 1.23
 
 require github.com/ghosind/go-assert v1.1.1
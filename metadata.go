@@ -1,16 +1,40 @@
 package csv
 
 import (
+	"cmp"
+	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type fieldMeta struct {
-	Index  int
-	Name   string
-	Type   reflect.Type
-	Format string
+	Index     []int
+	Name      string
+	Type      reflect.Type
+	Format    string
+	Required  bool
+	Default   string
+	OmitEmpty bool
+	Inline    bool
+	Aliases   []string
+	Position  int
+
+	TrueToken  string
+	FalseToken string
+	StrictBool bool
+
+	Number    NumberFormat
+	FloatFmt  byte
+	Precision int
+	Base      int
+	Bits      uint
+	Location  *time.Location
+
+	tagged bool
 }
 
 var metadataCache sync.Map
@@ -21,59 +45,409 @@ func reflectMetadata(v reflect.Value) ([]*fieldMeta, error) {
 		return nil, err
 	}
 
+	if ty.Kind() != reflect.Struct {
+		return nil, ErrInvalidType
+	}
+
 	if meta, ok := metadataCache.Load(ty); ok {
 		return meta.([]*fieldMeta), nil
 	}
 
-	metas := make([]*fieldMeta, 0, ty.NumField())
-	for i := 0; i < ty.NumField(); i++ {
-		f := ty.Field(i)
-		if f.PkgPath != "" { // unexported
-			continue
-		}
-		tag := f.Tag.Get("csv")
-		if tag == "-" {
-			continue
+	metas, err := typeFields(ty)
+	if err != nil {
+		return nil, err
+	}
+	metadataCache.Store(ty, metas)
+
+	return metas, nil
+}
+
+// typeFields returns the fields metadata of the struct type. The fields of
+// the anonymous embedded structs are promoted following the same rules as
+// encoding/json: a field at a shallower depth shadows the deeper fields with
+// the same name, and a tagged field dominates the untagged fields at the same
+// depth. The fields that cannot be resolved are omitted.
+//
+// The fields of a named struct field with the inline option are expanded too,
+// with the name of the struct field and a dot as the prefix of their names.
+//
+// It returns an error if the tag of any field has an invalid option.
+func typeFields(t reflect.Type) ([]*fieldMeta, error) {
+	type embedded struct {
+		typ    reflect.Type
+		index  []int
+		prefix string
+	}
+	type embeddedKey struct {
+		typ    reflect.Type
+		prefix string
+	}
+
+	current := []embedded{}
+	next := []embedded{{typ: t}}
+	count := map[embeddedKey]int{}
+	nextCount := map[embeddedKey]int{}
+	visited := map[embeddedKey]bool{}
+
+	var fields []*fieldMeta
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[embeddedKey]int{}
+
+		for _, f := range current {
+			key := embeddedKey{f.typ, f.prefix}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("csv")
+				if tag == "-" {
+					continue
+				}
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				fm, err := parseFieldTag(tag)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", sf.Name, err)
+				}
+				inline := ft.Kind() == reflect.Struct && !isValueType(ft) &&
+					((sf.Anonymous && fm.Name == "") || (sf.IsExported() && fm.Inline))
+				if !inline {
+					if !sf.IsExported() {
+						// unexported embedded struct is only walked for its
+						// promoted fields
+						continue
+					}
+					if fm.Name == "" {
+						fm.Name = sf.Name
+					}
+					fm.Name = f.prefix + fm.Name
+					fm.Index = index
+					fm.Type = sf.Type
+
+					fields = append(fields, fm)
+					if count[key] > 1 {
+						// the struct is embedded multiple times at the same
+						// level, so its fields annihilate each other
+						fields = append(fields, fm)
+					}
+					continue
+				}
+
+				prefix := f.prefix
+				if fm.Inline && (!sf.Anonymous || fm.Name != "") {
+					if fm.Name == "" {
+						fm.Name = sf.Name
+					}
+					prefix += fm.Name + "."
+				}
+
+				nextKey := embeddedKey{ft, prefix}
+				nextCount[nextKey]++
+				if nextCount[nextKey] == 1 {
+					next = append(next, embedded{typ: ft, index: index, prefix: prefix})
+				}
+			}
 		}
+	}
 
-		parts := strings.Split(tag, ",")
-		name := strings.TrimSpace(parts[0])
-		if name == "" {
-			name = f.Name
+	slices.SortStableFunc(fields, func(a, b *fieldMeta) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
 		}
-		format := ""
-		if len(parts) > 1 {
-			for _, part := range parts[1:] {
-				part = strings.TrimSpace(part)
-				if strings.HasPrefix(part, "format=") {
-					format = strings.TrimPrefix(part, "format=")
-				}
+		if c := cmp.Compare(len(a.Index), len(b.Index)); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
 			}
+			return 1
 		}
+		return slices.Compare(a.Index, b.Index)
+	})
 
-		fm := &fieldMeta{Index: i, Name: name, Type: f.Type, Format: format}
-		metas = append(metas, fm)
+	metas := make([]*fieldMeta, 0, len(fields))
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].Name == fields[i].Name {
+			j++
+		}
+		if dominant, ok := dominantField(fields[i:j]); ok {
+			metas = append(metas, dominant)
+		}
+		i = j
 	}
 
-	metadataCache.Store(ty, metas)
+	slices.SortFunc(metas, func(a, b *fieldMeta) int {
+		return slices.Compare(a.Index, b.Index)
+	})
 
 	return metas, nil
 }
 
+// dominantField returns the field that dominates the other fields with the
+// same name. The fields are sorted in the order of depth and tagging.
+func dominantField(fields []*fieldMeta) (*fieldMeta, bool) {
+	if len(fields) > 1 && len(fields[0].Index) == len(fields[1].Index) &&
+		fields[0].tagged == fields[1].tagged {
+		return nil, false
+	}
+	return fields[0], true
+}
+
+// newFieldMeta returns the field metadata with the default options.
+func newFieldMeta(name string) *fieldMeta {
+	return &fieldMeta{Name: name, Position: -1, Precision: -1, Base: 10}
+}
+
+// parseFieldTag parses the csv tag of a struct field.
+func parseFieldTag(tag string) (*fieldMeta, error) {
+	parts := strings.Split(tag, ",")
+	name := strings.TrimSpace(parts[0])
+	fm := newFieldMeta(name)
+	fm.tagged = name != ""
+
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "format="):
+			fm.Format = strings.TrimPrefix(part, "format=")
+		case strings.HasPrefix(part, "alias="):
+			fm.Aliases = strings.Split(strings.TrimPrefix(part, "alias="), "|")
+		case strings.HasPrefix(part, "index="):
+			pos, err := strconv.Atoi(strings.TrimPrefix(part, "index="))
+			if err != nil || pos < 0 {
+				return nil, newInvalidTagError(part, err)
+			}
+			fm.Position = pos
+		case strings.HasPrefix(part, "true="):
+			fm.TrueToken = strings.TrimPrefix(part, "true=")
+		case strings.HasPrefix(part, "false="):
+			fm.FalseToken = strings.TrimPrefix(part, "false=")
+		case strings.HasPrefix(part, "decimal="):
+			r, ok := parseSeparator(strings.TrimPrefix(part, "decimal="))
+			if !ok || r == NoGroup {
+				return nil, newInvalidTagError(part, nil)
+			}
+			fm.Number.Decimal = r
+		case strings.HasPrefix(part, "group="):
+			r, ok := parseSeparator(strings.TrimPrefix(part, "group="))
+			if !ok {
+				return nil, newInvalidTagError(part, nil)
+			}
+			fm.Number.Group = r
+		case strings.HasPrefix(part, "fmt="):
+			f := strings.TrimPrefix(part, "fmt=")
+			if len(f) != 1 || !strings.Contains("eEfgGxX", f) {
+				return nil, newInvalidTagError(part, nil)
+			}
+			fm.FloatFmt = f[0]
+		case strings.HasPrefix(part, "prec="):
+			prec, err := strconv.Atoi(strings.TrimPrefix(part, "prec="))
+			if err != nil || prec < 0 {
+				return nil, newInvalidTagError(part, err)
+			}
+			fm.Precision = prec
+		case strings.HasPrefix(part, "base="):
+			base, err := strconv.Atoi(strings.TrimPrefix(part, "base="))
+			if err != nil || (base != 0 && (base < 2 || base > 36)) {
+				return nil, newInvalidTagError(part, err)
+			}
+			fm.Base = base
+		case strings.HasPrefix(part, "bits="):
+			bits, err := strconv.ParseUint(strings.TrimPrefix(part, "bits="), 10, 32)
+			if err != nil {
+				return nil, newInvalidTagError(part, err)
+			}
+			fm.Bits = uint(bits)
+		case strings.HasPrefix(part, "tz="):
+			loc, err := time.LoadLocation(strings.TrimPrefix(part, "tz="))
+			if err != nil {
+				return nil, newInvalidTagError(part, err)
+			}
+			fm.Location = loc
+		case strings.HasPrefix(part, "currency="):
+			fm.Number.Currency = strings.TrimPrefix(part, "currency=")
+		case strings.HasPrefix(part, "default="):
+			fm.Default = strings.TrimPrefix(part, "default=")
+		case part == "required":
+			fm.Required = true
+		case part == "omitempty":
+			fm.OmitEmpty = true
+		case part == "inline":
+			fm.Inline = true
+		case part == "percent":
+			fm.Number.Percent = true
+		}
+	}
+
+	return fm, nil
+}
+
+// withDefaults returns a copy of the field metadata, with the formatting
+// options not set by the tag filled by the default options.
+func (m *fieldMeta) withDefaults(defaults *fieldMeta) *fieldMeta {
+	fm := *m
+	if fm.TrueToken == "" && fm.FalseToken == "" {
+		fm.TrueToken, fm.FalseToken = defaults.TrueToken, defaults.FalseToken
+	}
+	fm.StrictBool = fm.StrictBool || defaults.StrictBool
+	fm.Number = fm.Number.withDefaults(defaults.Number)
+	if fm.FloatFmt == 0 {
+		fm.FloatFmt = defaults.FloatFmt
+	}
+	if fm.Precision < 0 {
+		fm.Precision = defaults.Precision
+	}
+
+	return &fm
+}
+
+// mergeMetaFields returns the fields metadata with the default options. It
+// returns the fields metadata unchanged if there is no default option.
+func mergeMetaFields(meta []*fieldMeta, defaults *fieldMeta) []*fieldMeta {
+	if defaults == nil {
+		return meta
+	}
+
+	merged := make([]*fieldMeta, len(meta))
+	for i, m := range meta {
+		if m != nil {
+			merged[i] = m.withDefaults(defaults)
+		}
+	}
+	return merged
+}
+
+// positionalMetaFields returns the fields metadata laid out by the column
+// positions of the index tag option, with nil for the positions without a
+// field. The fields without the index option follow the last indexed position
+// in the declaration order. It returns the fields metadata unchanged if no
+// field has the index option.
+func positionalMetaFields(meta []*fieldMeta) ([]*fieldMeta, error) {
+	size := 0
+	for _, m := range meta {
+		size = max(size, m.Position+1)
+	}
+	if size == 0 {
+		return meta, nil
+	}
+
+	layout := make([]*fieldMeta, size)
+	for _, m := range meta {
+		if m.Position < 0 {
+			layout = append(layout, m)
+			continue
+		}
+		if layout[m.Position] != nil {
+			return nil, fmt.Errorf("%w: fields %s and %s have the same index %d",
+				ErrDuplicateColumn, layout[m.Position].Name, m.Name, m.Position)
+		}
+		layout[m.Position] = m
+	}
+
+	return layout, nil
+}
+
+// isValueType reports whether the struct type is encoded as a single value
+// instead of being flattened when it's embedded.
+func isValueType(t reflect.Type) bool {
+	if t.ConvertibleTo(timeType) {
+		return true
+	}
+
+	pt := reflect.PointerTo(t)
+	return pt.Implements(marshalerType) || pt.Implements(textMarshalerType) ||
+		pt.Implements(unmarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+// fieldByIndex returns the nested field of the struct by the index sequence,
+// and allocates the nil embedded struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, ErrCannotSet
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, nil
+}
+
+// fieldByIndexNoAlloc returns the nested field of the struct by the index
+// sequence. It reports false if any embedded struct pointer on the way is nil.
+func fieldByIndexNoAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
 func getValueType(v reflect.Value) (reflect.Type, error) {
 	t := v.Type()
 	for t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
+	if isDynamicType(t) {
+		return t, nil
+	}
 	if t.Kind() == reflect.Chan || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
 		t = t.Elem()
 	}
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
-	if t.Kind() != reflect.Struct {
+	if t.Kind() != reflect.Struct && !isDynamicType(t) {
 		return nil, ErrInvalidType
 	}
 
 	return t, nil
 }
+
+// isDynamicType reports whether the type is a record type without fixed
+// columns: a map keyed by the column names with string or interface{} values,
+// or a string slice.
+func isDynamicType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Map:
+		elem := t.Elem()
+		return t.Key().Kind() == reflect.String &&
+			(elem.Kind() == reflect.String || (elem.Kind() == reflect.Interface && elem.NumMethod() == 0))
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
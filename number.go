@@ -0,0 +1,161 @@
+package csv
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NoGroup is the grouping separator to disable the digit grouping of a field,
+// overriding the default grouping separator set by WithNumberFormat.
+const NoGroup rune = -1
+
+// NumberFormat describes the locale format of the numbers, for example
+// "1.234,56" with the comma decimal separator and the dot grouping separator.
+type NumberFormat struct {
+	// Decimal is the decimal separator, the default is '.'.
+	Decimal rune
+	// Group is the separator between the groups of thousands. There is no digit
+	// grouping if it's zero or NoGroup.
+	Group rune
+	// Currency is the currency symbol written before the numbers. The decoder
+	// accepts the symbol before or after the numbers.
+	Currency string
+	// Percent sets whether to write the percent sign after the numbers. The
+	// values are not scaled, so "15%" is the number 15.
+	Percent bool
+}
+
+// separators maps the names of the separators for the tag options, since the
+// comma cannot be written in the tag.
+var separators = map[string]rune{
+	"comma":      ',',
+	"dot":        '.',
+	"period":     '.',
+	"space":      ' ',
+	"apostrophe": '\'',
+	"underscore": '_',
+	"none":       NoGroup,
+}
+
+// parseSeparator parses the separator of the decimal= or group= tag option,
+// either a name of the separators or a single character.
+func parseSeparator(s string) (rune, bool) {
+	if r, ok := separators[s]; ok {
+		return r, true
+	}
+	if r, size := utf8.DecodeRuneInString(s); r != utf8.RuneError && size == len(s) {
+		return r, true
+	}
+	return 0, false
+}
+
+// withDefaults returns the number format with the options not set filled by
+// the default options.
+func (f NumberFormat) withDefaults(defaults NumberFormat) NumberFormat {
+	if f.Decimal == 0 {
+		f.Decimal = defaults.Decimal
+	}
+	if f.Group == 0 {
+		f.Group = defaults.Group
+	}
+	if f.Currency == "" {
+		f.Currency = defaults.Currency
+	}
+	f.Percent = f.Percent || defaults.Percent
+	return f
+}
+
+// normalize strips the currency and percent symbols and the grouping
+// separators from the number, and replaces the decimal separator with '.' to
+// make it parsable by strconv.
+func (f NumberFormat) normalize(s string) string {
+	if f == (NumberFormat{}) {
+		return s
+	}
+
+	s = strings.TrimSpace(s)
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	if f.Currency != "" {
+		if trimmed, ok := strings.CutPrefix(s, f.Currency); ok {
+			s = strings.TrimSpace(trimmed)
+			if sign == "" && (strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+")) {
+				sign, s = s[:1], s[1:]
+			}
+		} else {
+			s = strings.TrimSpace(strings.TrimSuffix(s, f.Currency))
+		}
+	}
+	if f.Percent {
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	}
+	if f.Group > 0 {
+		s = strings.ReplaceAll(s, string(f.Group), "")
+	}
+	if f.Decimal > 0 && f.Decimal != '.' {
+		s = strings.Replace(s, string(f.Decimal), ".", 1)
+	}
+
+	return sign + s
+}
+
+// localize formats the number written by strconv in the number format.
+func (f NumberFormat) localize(s string) string {
+	if f == (NumberFormat{}) {
+		return s
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	digits, rest := s, ""
+	if i := strings.IndexAny(s, ".eEpP"); i >= 0 {
+		digits, rest = s[:i], s[i:]
+	}
+	if f.Group > 0 && len(digits) > 3 && isDigits(digits) {
+		digits = groupDigits(digits, f.Group)
+	}
+	if f.Decimal > 0 && strings.HasPrefix(rest, ".") {
+		rest = string(f.Decimal) + rest[1:]
+	}
+
+	s = sign + f.Currency + digits + rest
+	if f.Percent {
+		s += "%"
+	}
+	return s
+}
+
+// groupDigits inserts the separator between the groups of thousands.
+func groupDigits(digits string, sep rune) string {
+	var sb strings.Builder
+	head := len(digits) % 3
+	if head == 0 {
+		head = 3
+	}
+	sb.WriteString(digits[:head])
+	for i := head; i < len(digits); i += 3 {
+		sb.WriteRune(sep)
+		sb.WriteString(digits[i : i+3])
+	}
+	return sb.String()
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDecimalFormat reports whether the strconv float format writes the numbers
+// in decimal notation, which the number format applies to. The default format
+// is the decimal 'f'.
+func isDecimalFormat(format byte) bool {
+	return format != 'b' && format != 'x' && format != 'X'
+}
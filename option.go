@@ -1,16 +1,59 @@
 package csv
 
+import "strings"
+
 type csvBuilder struct {
-	comma    rune
-	useCRLF  bool
-	noHeader bool
+	comma            rune
+	comment          rune
+	lazyQuotes       bool
+	trimLeadingSpace bool
+	fieldsPerRecord  int
+	reuseRecord      bool
+	useCRLF          bool
+	bom              bool
+	charset          Charset
+	noHeader         bool
+	strictHeader     bool
+	forceHeader      bool
+	lenientHeader    bool
+	nullString       string
+
+	collectErrors bool
+	maxErrors     int
+
+	keyOrder    func(a, b string) int
+	columns     []string
+	headerNames map[string]string
+
+	// default formatting options of the fields
+	fieldDefaults *fieldMeta
 }
 
 func newCSVBuilder(opts ...CSVOption) *csvBuilder {
 	builder := &csvBuilder{
-		comma:    ',',
-		useCRLF:  false,
-		noHeader: false,
+		comma:            ',',
+		comment:          0,
+		lazyQuotes:       false,
+		trimLeadingSpace: false,
+		fieldsPerRecord:  0,
+		reuseRecord:      false,
+		useCRLF:          false,
+		bom:              false,
+		charset:          UTF8,
+		noHeader:         false,
+		strictHeader:     false,
+		forceHeader:      false,
+		lenientHeader:    false,
+		nullString:       "",
+
+		collectErrors: false,
+		maxErrors:     0,
+
+		keyOrder:    strings.Compare,
+		columns:     nil,
+		headerNames: nil,
+
+		fieldDefaults: nil,
 	}
 
 	for _, opt := range opts {
@@ -29,16 +72,192 @@ func WithComma(r rune) CSVOption {
 	}
 }
 
-// WithCRLF sets whether to use \r\n as the line terminator for the CSV encoder/decoder.
+// WithComment sets the comment character for the CSV decoder. Lines beginning
+// with the comment character without preceding whitespace are ignored.
+func WithComment(r rune) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.comment = r
+	}
+}
+
+// WithLazyQuotes sets whether the CSV decoder allows a quote to appear in an
+// unquoted field, and a non-doubled quote to appear in a quoted field.
+func WithLazyQuotes(lazyQuotes bool) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.lazyQuotes = lazyQuotes
+	}
+}
+
+// WithTrimLeadingSpace sets whether the CSV decoder ignores the leading white
+// space of the fields.
+func WithTrimLeadingSpace(trim bool) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.trimLeadingSpace = trim
+	}
+}
+
+// WithFieldsPerRecord sets the number of fields per record for the CSV
+// decoder. If n is positive, each record must have n fields. If n is 0, each
+// record must have the same number of fields as the first record. If n is
+// negative, records may have a variable number of fields.
+func WithFieldsPerRecord(n int) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.fieldsPerRecord = n
+	}
+}
+
+// WithReuseRecord sets whether the CSV decoder reuses the backing array of
+// the records between reads for performance.
+func WithReuseRecord(reuse bool) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.reuseRecord = reuse
+	}
+}
+
+// WithCRLF sets whether to use \r\n as the line terminator for the CSV encoder.
+// It has no effect on the CSV decoder, which accepts both \n and \r\n.
 func WithCRLF(useCRLF bool) CSVOption {
 	return func(cb *csvBuilder) {
 		cb.useCRLF = useCRLF
 	}
 }
 
+// WithBOM sets whether the CSV encoder writes the byte order mark at the
+// beginning of the output. It has no effect for the character sets without
+// byte order mark. The CSV decoder always strips the byte order mark from the
+// input.
+func WithBOM(bom bool) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.bom = bom
+	}
+}
+
+// WithCharset sets the character set of the input of the CSV decoder or the
+// output of the CSV encoder. The decoder also detects the UTF-16 byte order
+// mark for the UTF-8 and UTF-16 character sets. The byte offsets reported by
+// DecodeError are the offsets in the input transcoded to UTF-8.
+func WithCharset(charset Charset) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.charset = charset
+	}
+}
+
 // WithNoHeader sets whether to omit the header row in the CSV encoding.
 func WithNoHeader(noHeader bool) CSVOption {
 	return func(cb *csvBuilder) {
 		cb.noHeader = noHeader
 	}
 }
+
+// WithStrictHeader sets whether the CSV decoder rejects the header row if it
+// contains columns without a matching field, or if any field has no matching
+// column.
+func WithStrictHeader(strict bool) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.strictHeader = strict
+	}
+}
+
+// WithLenientHeader sets whether the CSV decoder matches the columns of the
+// header row with the field names and aliases case-insensitively, ignoring
+// the leading and trailing whitespace of the columns.
+func WithLenientHeader(lenient bool) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.lenientHeader = lenient
+	}
+}
+
+// WithNullString sets the token that represents a nil value. The CSV encoder
+// writes it for nil pointers, and the CSV decoder sets fields to nil (or the
+// zero value for non-pointer fields) when it reads it. The default is an empty
+// string.
+func WithNullString(s string) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.nullString = s
+	}
+}
+
+// WithCollectErrors makes the CSV decoder skip invalid records instead of
+// stopping at the first error, and return all errors together as a
+// *DecodeErrors. The decoder stops once it has collected max errors; a
+// non-positive max means no limit.
+func WithCollectErrors(max int) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.collectErrors = true
+		cb.maxErrors = max
+	}
+}
+
+// WithKeyOrder sets the comparison function to sort the columns of the maps
+// for the CSV encoder. The default is the lexicographic order of the keys.
+func WithKeyOrder(cmp func(a, b string) int) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.keyOrder = cmp
+	}
+}
+
+// WithColumns sets the columns, and their order, to write by the CSV encoder.
+// For structs, the columns are the names of the fields, and the fields out of
+// the columns are not written.
+func WithColumns(columns ...string) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.columns = columns
+	}
+}
+
+// WithHeaderNames sets the names to write in the header row instead of the
+// column names for the CSV encoder. The map is keyed by the column names.
+func WithHeaderNames(names map[string]string) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.headerNames = names
+	}
+}
+
+// defaults returns the default formatting options of the fields to set.
+func (cb *csvBuilder) defaults() *fieldMeta {
+	if cb.fieldDefaults == nil {
+		cb.fieldDefaults = newFieldMeta("")
+	}
+	return cb.fieldDefaults
+}
+
+// WithBoolTokens sets the tokens of the boolean values for the CSV encoder and
+// decoder. The decoder matches the tokens case-insensitively. The fields with
+// the true= or false= tag options use their own tokens, and an empty token
+// falls back to "true" or "false".
+func WithBoolTokens(trueToken, falseToken string) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.defaults().TrueToken = trueToken
+		cb.defaults().FalseToken = falseToken
+	}
+}
+
+// WithStrictBool sets whether the CSV decoder returns an error for the
+// unrecognized or empty boolean values instead of decoding them as false. Use
+// *bool fields or the default= tag option for the optional boolean values.
+func WithStrictBool(strict bool) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.defaults().StrictBool = strict
+	}
+}
+
+// WithNumberFormat sets the default locale format of the numbers for the CSV
+// encoder and decoder. The fields with the decimal=, group=, currency= or
+// percent tag options override the corresponding settings, and a field can
+// disable the digit grouping with group=none.
+func WithNumberFormat(format NumberFormat) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.defaults().Number = format
+	}
+}
+
+// WithFloatFormat sets the default format and precision of the floating-point
+// numbers for the CSV encoder, with the same meaning as the fmt and prec
+// arguments of strconv.FormatFloat. The fields with the fmt=, prec= or printf
+// style format= tag options override the corresponding settings.
+func WithFloatFormat(fmt byte, prec int) CSVOption {
+	return func(cb *csvBuilder) {
+		cb.defaults().FloatFmt = fmt
+		cb.defaults().Precision = prec
+	}
+}
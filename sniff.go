@@ -0,0 +1,222 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// sniffSize is the maximum number of bytes read by Sniff.
+const sniffSize = 64 * 1024
+
+// sniffDelimiters are the candidate delimiters of Sniff, in the order of
+// preference.
+var sniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// Dialect describes the format of a CSV input, as inferred by Sniff.
+type Dialect struct {
+	// Comma is the field delimiter.
+	Comma rune
+	// UseCRLF reports whether the lines are terminated by \r\n.
+	UseCRLF bool
+	// HasHeader reports whether the first record is a header row.
+	HasHeader bool
+	// HasBOM reports whether the input begins with a byte order mark.
+	HasBOM bool
+	// Charset is the character set of the input, detected by the byte order
+	// mark. It's UTF8 if the input has no UTF-16 byte order mark.
+	Charset Charset
+}
+
+// Options returns the options to decode or encode CSV with the dialect.
+func (d Dialect) Options() []CSVOption {
+	opts := []CSVOption{
+		WithComma(d.Comma),
+		WithCRLF(d.UseCRLF),
+		WithBOM(d.HasBOM),
+		WithCharset(d.Charset),
+		WithNoHeader(!d.HasHeader),
+	}
+	if d.HasHeader {
+		opts = append(opts, func(cb *csvBuilder) {
+			// the first line is the header even if no column matches a field
+			cb.forceHeader = true
+		})
+	}
+
+	return opts
+}
+
+// Sniff reads a sample of the first 64 KiB of r, and infers the dialect of the
+// CSV input: the delimiter (comma, semicolon, tab or pipe), the line
+// terminator, the presence of a header row, and the byte order mark.
+//
+// Sniff consumes the sample from r, so the caller should rewind r or use
+// io.MultiReader to decode the whole input after sniffing.
+func Sniff(r io.Reader) (Dialect, error) {
+	buf := make([]byte, sniffSize)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return Dialect{}, err
+	}
+	buf = buf[:n]
+	truncated := n == sniffSize
+
+	dialect := Dialect{Comma: ',', Charset: UTF8}
+	switch {
+	case bytes.HasPrefix(buf, utf8BOM):
+		dialect.HasBOM = true
+	case bytes.HasPrefix(buf, utf16LEBOM):
+		dialect.HasBOM = true
+		dialect.Charset = UTF16LE
+	case bytes.HasPrefix(buf, utf16BEBOM):
+		dialect.HasBOM = true
+		dialect.Charset = UTF16BE
+	}
+
+	sample, err := io.ReadAll(newCharsetReader(bytes.NewReader(buf), dialect.Charset))
+	if err != nil {
+		return Dialect{}, err
+	}
+	if truncated {
+		// drop the last line that may be incomplete
+		if i := bytes.LastIndexByte(sample, '\n'); i >= 0 {
+			sample = sample[:i+1]
+		}
+	}
+	dialect.UseCRLF = bytes.Contains(sample, []byte("\r\n"))
+
+	var records [][]string
+	bestScore := 0.0
+	for _, delim := range sniffDelimiters {
+		rs, score := sniffDelimiter(sample, delim)
+		if score > bestScore {
+			dialect.Comma, records, bestScore = delim, rs, score
+		}
+	}
+	if records == nil {
+		records, _ = sniffDelimiter(sample, dialect.Comma)
+	}
+
+	dialect.HasHeader = sniffHeader(records)
+
+	return dialect, nil
+}
+
+// sniffDelimiter parses the sample with the delimiter, and scores how
+// consistently the records are split into multiple fields by the delimiter.
+func sniffDelimiter(sample []byte, delim rune) ([][]string, float64) {
+	reader := csv.NewReader(bytes.NewReader(sample))
+	reader.Comma = delim
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, 0
+	}
+
+	counts := make(map[int]int)
+	mode := 0
+	for _, record := range records {
+		counts[len(record)]++
+		if counts[len(record)] > counts[mode] || (counts[len(record)] == counts[mode] && len(record) > mode) {
+			mode = len(record)
+		}
+	}
+	if mode <= 1 {
+		return records, 0
+	}
+
+	// the consistency of the number of fields, with a slight preference for
+	// more fields
+	score := float64(counts[mode])/float64(len(records)) + float64(mode)/1e6
+	return records, score
+}
+
+// sniffHeader guesses whether the first record is a header row. Each column
+// with a consistent type in the other records votes for a header if the cell
+// of the first record doesn't have the type: numbers for the numeric columns,
+// and the length for the columns of a fixed length.
+func sniffHeader(records [][]string) bool {
+	if len(records) == 0 {
+		return false
+	}
+
+	header := records[0]
+	if len(records) == 1 {
+		// a single record is a header if none of its cells is a number
+		for _, cell := range header {
+			if cell == "" || isNumeric(cell) {
+				return false
+			}
+		}
+		return true
+	}
+
+	votes, numericColumn := 0, false
+	for i, cell := range header {
+		numeric, length := true, -1
+		for _, record := range records[1:] {
+			if i >= len(record) {
+				continue
+			}
+			if !isNumeric(record[i]) {
+				numeric = false
+			}
+
+			l := utf8.RuneCountInString(record[i])
+			if length == -1 {
+				length = l
+			} else if length != l {
+				length = -2
+			}
+		}
+
+		switch {
+		case numeric && length != -1:
+			numericColumn = true
+			if isNumeric(cell) {
+				votes--
+			} else {
+				votes++
+			}
+		case length >= 0:
+			if utf8.RuneCountInString(cell) == length {
+				votes--
+			} else {
+				votes++
+			}
+		}
+	}
+
+	if votes == 0 && numericColumn {
+		// break the tie with a first record of distinct textual cells, which
+		// is unlikely to be data above a numeric column
+		return isLabelRecord(header)
+	}
+
+	return votes > 0
+}
+
+// isLabelRecord reports whether the cells of the record are distinct,
+// non-empty and non-numeric.
+func isLabelRecord(record []string) bool {
+	seen := make(map[string]bool, len(record))
+	for _, cell := range record {
+		if cell == "" || isNumeric(cell) || seen[cell] {
+			return false
+		}
+		seen[cell] = true
+	}
+	return true
+}
+
+// isNumeric reports whether the string is a number.
+func isNumeric(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
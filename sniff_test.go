@@ -0,0 +1,83 @@
+package csv_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ghosind/go-assert"
+	"github.com/ghosind/go-csv"
+)
+
+func TestSniffSemicolonWithHeader(t *testing.T) {
+	a := assert.New(t)
+	data := "id;name;salary\n1;John Doe;5500,50\n2;Jane Smith;3000,00\n"
+
+	dialect, err := csv.Sniff(strings.NewReader(data))
+	a.NilNow(err)
+	a.EqualNow(csv.Dialect{Comma: ';', HasHeader: true, Charset: csv.UTF8}, dialect)
+}
+
+func TestSniffTabWithoutHeader(t *testing.T) {
+	a := assert.New(t)
+	data := "1\tJohn Doe\t30\n2\tJane Smith\t25\n3\tBob Brown\t40\n"
+
+	dialect, err := csv.Sniff(strings.NewReader(data))
+	a.NilNow(err)
+	a.EqualNow(csv.Dialect{Comma: '\t', HasHeader: false, Charset: csv.UTF8}, dialect)
+}
+
+func TestSniffHeaderWithTiedVotes(t *testing.T) {
+	a := assert.New(t)
+	data := "name;city;zip\nJohn;\"Paris, FR\";75001\nJane;Berlin;10115\n"
+
+	dialect, err := csv.Sniff(strings.NewReader(data))
+	a.NilNow(err)
+	a.EqualNow(csv.Dialect{Comma: ';', HasHeader: true, Charset: csv.UTF8}, dialect)
+
+	data = "John;\"Paris, FR\";75001\nJane;Berlin;10115\nJack;Lyon;69001\n"
+	dialect, err = csv.Sniff(strings.NewReader(data))
+	a.NilNow(err)
+	a.NotTrueNow(dialect.HasHeader)
+}
+
+func TestSniffPipeWithBOMAndCRLF(t *testing.T) {
+	a := assert.New(t)
+	data := "\xEF\xBB\xBFcode|country\r\nUS|United States\r\nFR|France\r\n"
+
+	dialect, err := csv.Sniff(strings.NewReader(data))
+	a.NilNow(err)
+	a.EqualNow(csv.Dialect{Comma: '|', UseCRLF: true, HasHeader: true, HasBOM: true, Charset: csv.UTF8}, dialect)
+}
+
+func TestSniffUTF16(t *testing.T) {
+	a := assert.New(t)
+	data := []byte{
+		0xFF, 0xFE,
+		'I', 0, 'D', 0, ';', 0, 'N', 0, 'a', 0, 'm', 0, 'e', 0, '\n', 0,
+		'1', 0, ';', 0, 'J', 0, 'o', 0, 'e', 0, '\n', 0,
+	}
+
+	dialect, err := csv.Sniff(bytes.NewReader(data))
+	a.NilNow(err)
+	a.EqualNow(csv.Dialect{Comma: ';', HasHeader: true, HasBOM: true, Charset: csv.UTF16LE}, dialect)
+}
+
+func TestSniffOptionsWithRenamedHeader(t *testing.T) {
+	a := assert.New(t)
+	data := "Employee ID;Employee Name\n1;John Doe\n2;Jane Smith\n"
+
+	dialect, err := csv.Sniff(strings.NewReader(data))
+	a.NilNow(err)
+	a.TrueNow(dialect.HasHeader)
+
+	var rows []NoTagStruct
+	decoder := csv.NewDecoder(strings.NewReader(data), dialect.Options()...)
+	err = decoder.Decode(&rows)
+	a.NilNow(err)
+	a.EqualNow([]NoTagStruct{{}, {}}, rows)
+
+	decoder = csv.NewDecoder(strings.NewReader(data), csv.WithComma(';'))
+	err = decoder.Decode(&rows)
+	a.NotNilNow(err)
+}